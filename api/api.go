@@ -1,37 +1,70 @@
 package api
 
 import (
+	"SecCrawler/api/auth"
 	"SecCrawler/api/controllers"
-	"SecCrawler/config"
-	"SecCrawler/utils"
+	"SecCrawler/api/web"
+	"net/http"
 
-	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 )
 
 func RouterInit(r *gin.Engine) {
+	setTrustedProxies(r)
+	r.Use(ipFilter())
 	setCors(r)
-	api := r.Group("/api", auth)
+	ipLimit, keyLimit := ipRateLimit(), keyRateLimit()
 
-	public := api.Group("/crawler")
+	// 内置管理页面，页面本身不需要鉴权，数据通过带 Authorization 头的 API 获取
+	r.StaticFS("/dashboard", web.FS())
+	r.GET("/", func(c *gin.Context) {
+		c.Redirect(http.StatusFound, "/dashboard/")
+	})
+
+	api := r.Group("/api", ipLimit, auth.Middleware(nil), keyLimit)
+	read := api.Group("", auth.Require(auth.ScopeRead))
+
+	public := read.Group("/crawler")
 	{
 		public.GET("/getArticles/:site", controllers.GetArticles)
+		public.GET("/list", controllers.ListCrawlers)
+		public.GET("/status/:site", controllers.GetCrawlerStatus)
 	}
-}
 
-func setCors(r *gin.Engine) {
-	conf := cors.DefaultConfig()
-	conf.AllowAllOrigins = true
-	conf.AllowHeaders = append(conf.AllowHeaders, "Authorization")
-	r.Use(cors.New(conf))
-}
+	article := read.Group("/article")
+	{
+		article.GET("/search", controllers.SearchArticles)
+	}
+
+	job := api.Group("/job")
+	{
+		job.POST("/run", auth.Require(auth.ScopeTrigger), controllers.RunJob)
+		job.GET("/list", auth.Require(auth.ScopeRead), controllers.ListJobs)
+		job.GET("/status/:id", auth.Require(auth.ScopeRead), controllers.GetJobStatus)
+	}
 
-func auth(c *gin.Context) {
-	key := c.GetHeader("Authorization")
+	read.GET("/feeds", controllers.ListFeeds)
 
-	if key != config.Cfg.Api.Auth {
-		utils.ErrorStrResp(c, utils.INVALID_AUTH_KEY, "Invalid auth key")
-		return
+	stream := read.Group("/stream")
+	{
+		stream.GET("", controllers.StreamArticles)
+		stream.GET("/ws", controllers.StreamArticlesWS)
+	}
+
+	// 订阅源供 RSS 阅读器使用，除 Authorization 头外也可在 token 参数中携带订阅源的 token
+	feed := r.Group("/api/feed", ipLimit, auth.Middleware(controllers.FeedKey), keyLimit, auth.Require(auth.ScopeRead))
+	{
+		feed.GET("/:format", controllers.GetFeed)
+		feed.GET("/:format/crawler/:site", controllers.GetFeed)
+		feed.GET("/:format/tag/:tag", controllers.GetFeed)
 	}
-	c.Next()
+
+	bot := read.Group("/bot")
+	{
+		bot.GET("/list", controllers.ListBots)
+		bot.GET("/status/:bot", controllers.GetBotStatus)
+		bot.GET("/history/:bot", controllers.GetBotHistory)
+	}
+
+	api.GET("/audit", auth.Require(auth.ScopeAdmin), controllers.GetAuditLog)
 }
@@ -0,0 +1,367 @@
+package auth
+
+import (
+	. "SecCrawler/config"
+	"SecCrawler/store"
+	"SecCrawler/utils"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// API Key 的权限，admin 包含全部权限。
+const (
+	ScopeRead    = "read"
+	ScopeTrigger = "trigger"
+	ScopeAdmin   = "admin"
+)
+
+const (
+	hashPrefix = "sha256:"
+	keyContext = "apiKey"
+	denyReason = "apiKeyDenied"
+)
+
+// Key 解析后的 API Key。
+type Key struct {
+	Name    string
+	Scopes  []string
+	Sources []string  // 允许访问的爬虫，为空表示全部
+	Expires time.Time // 零值表示不过期
+	hash    []byte
+	secret  []byte // 生成订阅源 token 的密钥，由数据库中的随机密钥和 Key 的哈希派生
+	legacy  bool   // 来自 auth 配置项
+}
+
+var (
+	keys []*Key
+	// anonymous 没有配置任何密钥时所有请求都使用该 Key，与此前 auth 为空时的行为一致
+	anonymous *Key
+)
+
+// Hash 返回密钥在配置中保存的哈希。
+func Hash(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hashPrefix + hex.EncodeToString(sum[:])
+}
+
+// AuthInit 解析配置中的 API Key，配置有误时退出。
+func AuthInit() {
+	keys, anonymous = nil, nil
+	// 订阅源 token 不能只由配置中的哈希生成，否则能读取配置文件的人可以伪造 token
+	feedSecret, err := store.Secret("feed", sha256.Size)
+	if err != nil {
+		log.Fatalf("load feed secret error: %s\n", err.Error())
+	}
+	names := map[string]bool{}
+	for _, conf := range Cfg.Api.Keys {
+		key, err := parseKey(conf)
+		if err != nil {
+			log.Fatalf("api key [%s] error: %s\n", conf.Name, err.Error())
+		}
+		if names[strings.ToLower(key.Name)] {
+			log.Fatalf("api key [%s] error: duplicate name\n", key.Name)
+		}
+		names[strings.ToLower(key.Name)] = true
+		key.secret = deriveSecret(feedSecret, key.hash)
+		keys = append(keys, key)
+	}
+	if Cfg.Api.Auth != "" {
+		sum := sha256.Sum256([]byte(Cfg.Api.Auth))
+		keys = append(keys, &Key{
+			Name:   "auth",
+			Scopes: []string{ScopeAdmin},
+			hash:   sum[:],
+			secret: deriveSecret(feedSecret, sum[:]),
+			legacy: true,
+		})
+	}
+	if len(keys) == 0 {
+		anonymous = &Key{Name: "anonymous", Scopes: []string{ScopeAdmin}}
+		log.Printf("no api key configured, the api is open to everyone\n")
+	}
+	startAudit()
+}
+
+// deriveSecret 为每个 Key 派生生成订阅源 token 的密钥。
+func deriveSecret(feedSecret, hash []byte) []byte {
+	mac := hmac.New(sha256.New, feedSecret)
+	mac.Write(hash)
+	return mac.Sum(nil)
+}
+
+func parseKey(conf ApiKeyStruct) (*Key, error) {
+	if conf.Name == "" || strings.ContainsAny(conf.Name, ". ") {
+		return nil, errors.New("name should not be empty or contain dots and spaces")
+	}
+	if strings.EqualFold(conf.Name, "auth") || strings.EqualFold(conf.Name, "anonymous") {
+		return nil, errors.New("name is reserved")
+	}
+	if !strings.HasPrefix(conf.Hash, hashPrefix) {
+		return nil, fmt.Errorf("hash should start with %s, generate it with -hash", hashPrefix)
+	}
+	hash, err := hex.DecodeString(strings.TrimPrefix(conf.Hash, hashPrefix))
+	if err != nil || len(hash) != sha256.Size {
+		return nil, errors.New("invalid sha256 hash")
+	}
+	if len(conf.Scopes) == 0 {
+		return nil, errors.New("scopes should not be empty")
+	}
+	for _, scope := range conf.Scopes {
+		switch strings.ToLower(scope) {
+		case ScopeRead, ScopeTrigger, ScopeAdmin:
+		default:
+			return nil, fmt.Errorf("unknown scope [%s], should be read, trigger or admin", scope)
+		}
+	}
+
+	key := &Key{Name: conf.Name, Scopes: conf.Scopes, Sources: conf.Sources, hash: hash}
+	if conf.Expires != "" {
+		if key.Expires, err = time.Parse(time.RFC3339, conf.Expires); err != nil {
+			if key.Expires, err = time.ParseInLocation("2006-01-02", conf.Expires, Location); err != nil {
+				return nil, errors.New("expires should be RFC3339 or 2006-01-02")
+			}
+		}
+	}
+	return key, nil
+}
+
+// lookup 按密钥查找 API Key。与每个 Key 的哈希都比较一次，比较耗时与密钥内容和匹配位置无关。
+func lookup(raw string) (*Key, error) {
+	if anonymous != nil {
+		return anonymous, nil
+	}
+	sum := sha256.Sum256([]byte(raw))
+	var found *Key
+	for _, key := range keys {
+		if subtle.ConstantTimeCompare(sum[:], key.hash) == 1 {
+			found = key
+		}
+	}
+	if found == nil {
+		return nil, errors.New("Invalid auth key")
+	}
+	return found, found.valid()
+}
+
+func (key *Key) valid() error {
+	if !key.Expires.IsZero() && time.Now().After(key.Expires) {
+		return errors.New("The auth key has expired")
+	}
+	return nil
+}
+
+// Allows 判断 Key 是否拥有 scope 权限。
+func (key *Key) Allows(scope string) bool {
+	for _, s := range key.Scopes {
+		if strings.EqualFold(s, scope) || strings.EqualFold(s, ScopeAdmin) {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsSource 判断 Key 是否可以访问爬虫 source。
+func (key *Key) AllowsSource(source string) bool {
+	if len(key.Sources) == 0 {
+		return true
+	}
+	for _, s := range key.Sources {
+		if strings.EqualFold(s, source) {
+			return true
+		}
+	}
+	return false
+}
+
+// RestrictSources 将请求的爬虫限制在 Key 允许的范围内：requested 为空时返回允许的全部爬虫
+// （不限制时为空），请求了不允许的爬虫时返回该爬虫名称和 false。
+func (key *Key) RestrictSources(requested []string) ([]string, string, bool) {
+	if len(requested) == 0 {
+		return key.Sources, "", true
+	}
+	for _, source := range requested {
+		if !key.AllowsSource(source) {
+			return nil, source, false
+		}
+	}
+	return requested, "", true
+}
+
+// FeedToken 返回 Key 对订阅源 feed 的 token。auth 的 token 为签名本身，
+// 具名 Key 的 token 为 <名称>.<签名>，签名使用数据库中的随机密钥和 Key 的哈希派生的密钥，
+// 只能读取配置文件时无法伪造，token 泄露后不影响密钥和其他订阅源。
+func (key *Key) FeedToken(feed string) string {
+	mac := hmac.New(sha256.New, key.secret)
+	mac.Write([]byte("feed:" + feed))
+	signature := hex.EncodeToString(mac.Sum(nil))[:32]
+	if key.legacy || key == anonymous {
+		return signature
+	}
+	return key.Name + "." + signature
+}
+
+// lookupFeedToken 按订阅源 token 查找 API Key。
+func lookupFeedToken(token, feed string) (*Key, error) {
+	if anonymous != nil {
+		return anonymous, nil
+	}
+	name := "auth"
+	if i := strings.LastIndexByte(token, '.'); i >= 0 {
+		name = token[:i]
+	}
+	for _, key := range keys {
+		if key.Name == name && hmac.Equal([]byte(token), []byte(key.FeedToken(feed))) {
+			return key, key.valid()
+		}
+	}
+	return nil, errors.New("Invalid feed token")
+}
+
+// Middleware 校验 Authorization 头中的 API Key 并记录使用情况。feed 不为空时，
+// 没有 Authorization 头的请求也可以使用 token 参数中由 feed 返回的订阅源对应的 token。
+func Middleware(feed func(*gin.Context) string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		var key *Key
+		var err error
+		if header := c.GetHeader("Authorization"); header != "" || feed == nil || c.Query("token") == "" {
+			key, err = lookup(header)
+		} else {
+			key, err = lookupFeedToken(c.Query("token"), feed(c))
+		}
+		if err != nil {
+			utils.ErrorStrResp(c, utils.INVALID_AUTH_KEY, err.Error())
+			c.Set(denyReason, err.Error())
+			audit(c, "", start)
+			return
+		}
+		c.Set(keyContext, key)
+		c.Next()
+		audit(c, key.Name, start)
+	}
+}
+
+// Require 要求请求的 API Key 拥有 scope 权限。
+func Require(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !Current(c).Allows(scope) {
+			Deny(c, fmt.Sprintf("The auth key does not have the %s scope", scope))
+			return
+		}
+		c.Next()
+	}
+}
+
+// Current 返回请求使用的 API Key。
+func Current(c *gin.Context) *Key {
+	return c.MustGet(keyContext).(*Key)
+}
+
+// Deny 拒绝请求并在使用记录中注明原因。
+func Deny(c *gin.Context, reason string) {
+	c.Set(denyReason, reason)
+	utils.ErrorStrResp(c, utils.PERMISSION_DENIED, reason)
+}
+
+// 使用记录先放入队列，由 auditWriter 批量写入，避免每个请求都单独提交一次写事务。
+const (
+	auditQueue    = 1024
+	auditBatch    = 100
+	auditInterval = time.Second
+)
+
+var (
+	auditMu      sync.RWMutex
+	auditEntries chan store.AuditEntry
+	auditDone    chan struct{}
+)
+
+// startAudit 启动批量写入使用记录的 goroutine。
+func startAudit() {
+	auditMu.Lock()
+	defer auditMu.Unlock()
+	if auditEntries != nil {
+		return
+	}
+	auditEntries = make(chan store.AuditEntry, auditQueue)
+	auditDone = make(chan struct{})
+	go auditWriter(auditEntries, auditDone)
+}
+
+// StopAudit 停止接收使用记录，并等待队列中的记录写入完毕。
+func StopAudit() {
+	auditMu.Lock()
+	entries, done := auditEntries, auditDone
+	auditEntries = nil
+	auditMu.Unlock()
+	if entries == nil {
+		return
+	}
+	close(entries)
+	<-done
+}
+
+// auditWriter 每积累 auditBatch 条或每隔 auditInterval 写入一次使用记录。
+func auditWriter(entries <-chan store.AuditEntry, done chan<- struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(auditInterval)
+	defer ticker.Stop()
+
+	var batch []store.AuditEntry
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := store.RecordAudit(batch...); err != nil {
+			log.Printf("record audit error: %s\n", err.Error())
+		}
+		batch = nil
+	}
+	for {
+		select {
+		case entry, ok := <-entries:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, entry)
+			if len(batch) >= auditBatch {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+func audit(c *gin.Context, name string, start time.Time) {
+	entry := store.AuditEntry{
+		Time:   start,
+		Key:    name,
+		IP:     c.ClientIP(),
+		Method: c.Request.Method,
+		Path:   c.Request.URL.Path, // 不记录查询参数，避免记录订阅源 token
+		Status: c.Writer.Status(),
+		Error:  c.GetString(denyReason),
+	}
+	auditMu.RLock()
+	defer auditMu.RUnlock()
+	if auditEntries == nil {
+		return
+	}
+	select {
+	case auditEntries <- entry:
+	default:
+		log.Printf("audit queue is full, drop entry [%s %s]\n", entry.Method, entry.Path)
+	}
+}
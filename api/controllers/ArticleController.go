@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"SecCrawler/api/auth"
+	"SecCrawler/config"
+	"SecCrawler/store"
+	"SecCrawler/utils"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SearchResp 历史文章检索结果，nextCursor 为空表示没有下一页。
+type SearchResp struct {
+	Articles   []store.Record `json:"articles"`
+	NextCursor string         `json:"nextCursor"`
+}
+
+// SearchArticles 检索历史文章。
+// 参数：q 全文检索、source 爬虫名称（可多个，逗号分隔）、tag、cve、from/to 时间范围
+// （RFC3339 或 2006-01-02）、limit 每页数量（默认 20，最大 100）、cursor 上一页返回的游标。
+func SearchArticles(c *gin.Context) {
+	query := store.SearchQuery{
+		Text:   c.Query("q"),
+		Tag:    c.Query("tag"),
+		CVE:    c.Query("cve"),
+		Cursor: c.Query("cursor"),
+		Limit:  20,
+	}
+	for _, source := range c.QueryArray("source") {
+		for _, name := range strings.Split(source, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				query.Sources = append(query.Sources, name)
+			}
+		}
+	}
+
+	sources, denied, ok := auth.Current(c).RestrictSources(query.Sources)
+	if !ok {
+		auth.Deny(c, "The auth key is not allowed to access "+denied)
+		return
+	}
+	query.Sources = sources
+
+	var err error
+	if query.From, err = parseTime(c.Query("from"), false); err != nil {
+		utils.ErrorStrResp(c, utils.INVALID_PARAMS, "Invalid from: "+err.Error())
+		return
+	}
+	if query.To, err = parseTime(c.Query("to"), true); err != nil {
+		utils.ErrorStrResp(c, utils.INVALID_PARAMS, "Invalid to: "+err.Error())
+		return
+	}
+	if limit := c.Query("limit"); limit != "" {
+		query.Limit, err = strconv.Atoi(limit)
+		if err != nil || query.Limit <= 0 || query.Limit > 100 {
+			utils.ErrorStrResp(c, utils.INVALID_PARAMS, "Invalid limit, should be 1-100")
+			return
+		}
+	}
+
+	articles, next, err := store.Search(query)
+	if err != nil {
+		utils.ErrorResp(c, utils.INVALID_PARAMS, err)
+		return
+	}
+	utils.SuccessResp(c, SearchResp{Articles: articles, NextCursor: next})
+}
+
+// parseTime 解析 RFC3339 或 2006-01-02 格式的时间，日期格式按配置的时区解析，
+// endOfDay 为 true 时取当天的最后时刻。
+func parseTime(value string, endOfDay bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, config.Location)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("should be RFC3339 or 2006-01-02")
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	return t, nil
+}
@@ -0,0 +1,29 @@
+package controllers
+
+import (
+	"SecCrawler/store"
+	"SecCrawler/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetAuditLog 返回最近的 API 使用记录，按时间从新到旧排列。
+// 参数：key API Key 名称、limit 数量（默认 100，最大 1000）。
+func GetAuditLog(c *gin.Context) {
+	limit := 100
+	if value := c.Query("limit"); value != "" {
+		var err error
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit <= 0 || limit > 1000 {
+			utils.ErrorStrResp(c, utils.INVALID_PARAMS, "Invalid limit, should be 1-1000")
+			return
+		}
+	}
+	entries, err := store.AuditLog(c.Query("key"), limit)
+	if err != nil {
+		utils.ErrorResp(c, utils.INVALID_PARAMS, err)
+		return
+	}
+	utils.SuccessResp(c, entries)
+}
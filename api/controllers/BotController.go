@@ -0,0 +1,131 @@
+package controllers
+
+import (
+	"SecCrawler/api/auth"
+	"SecCrawler/register"
+	"SecCrawler/scheduler"
+	"SecCrawler/store"
+	"SecCrawler/utils"
+	"sort"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BotInfo Bot的配置与推送状态。
+type BotInfo struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Enabled     bool     `json:"enabled"`
+	Schedules   []string `json:"schedules"` // 包含该Bot的推送计划
+	Pending     int      `json:"pending"`   // 待推送的文章数量
+	Delivered   int      `json:"delivered"` // 已推送的文章数量
+	RunInfo
+}
+
+// ListBots 列出所有Bot（包括未启用的）及其推送状态，只统计 API Key 可以访问的爬虫。
+func ListBots(c *gin.Context) {
+	key := auth.Current(c)
+	result := []BotInfo{}
+	for _, bot := range register.GetBotMap() {
+		info, err := botInfo(key, bot.Config(), true)
+		if err != nil {
+			utils.ErrorResp(c, utils.BOT_NOT_FOUND, err)
+			return
+		}
+		result = append(result, info)
+	}
+	for _, conf := range register.GetDisabledBotMap() {
+		info, err := botInfo(key, conf, false)
+		if err != nil {
+			utils.ErrorResp(c, utils.BOT_NOT_FOUND, err)
+			return
+		}
+		result = append(result, info)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	utils.SuccessResp(c, result)
+}
+
+// GetBotStatus 返回单个Bot的配置与推送状态，只统计 API Key 可以访问的爬虫。
+func GetBotStatus(c *gin.Context) {
+	botName := c.Params.ByName("bot")
+	conf, enabled := register.BotConfig{}, true
+	if bot, ok := register.GetBotMap()[botName]; ok {
+		conf = bot.Config()
+	} else if disabled, ok := register.GetDisabledBotMap()[botName]; ok {
+		conf, enabled = disabled, false
+	} else {
+		utils.ErrorStrResp(c, utils.BOT_NOT_FOUND, "The bot does not exist")
+		return
+	}
+	info, err := botInfo(auth.Current(c), conf, enabled)
+	if err != nil {
+		utils.ErrorResp(c, utils.BOT_NOT_FOUND, err)
+		return
+	}
+	utils.SuccessResp(c, info)
+}
+
+// GetBotHistory 返回Bot最近的推送记录，按时间从新到旧排列，limit 为数量（默认 50，最大 200），
+// 只返回 API Key 可以访问的爬虫的记录。
+func GetBotHistory(c *gin.Context) {
+	botName := c.Params.ByName("bot")
+	if _, ok := register.GetBotMap()[botName]; !ok {
+		if _, ok := register.GetDisabledBotMap()[botName]; !ok {
+			utils.ErrorStrResp(c, utils.BOT_NOT_FOUND, "The bot does not exist")
+			return
+		}
+	}
+	limit := 50
+	if value := c.Query("limit"); value != "" {
+		var err error
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit <= 0 || limit > 200 {
+			utils.ErrorStrResp(c, utils.INVALID_PARAMS, "Invalid limit, should be 1-200")
+			return
+		}
+	}
+	history, err := store.DeliveryHistory(botName, limit, auth.Current(c).AllowsSource)
+	if err != nil {
+		utils.ErrorResp(c, utils.BOT_NOT_FOUND, err)
+		return
+	}
+	utils.SuccessResp(c, history)
+}
+
+func botInfo(key *auth.Key, conf register.BotConfig, enabled bool) (BotInfo, error) {
+	status, err := store.GetBotStatus(conf.Name)
+	if err != nil {
+		return BotInfo{}, err
+	}
+	pending, delivered, err := store.BotCounts(conf.Name, key.AllowsSource)
+	if err != nil {
+		return BotInfo{}, err
+	}
+	// Bot的运行状态汇总了所有爬虫，最近的错误可能来自 API Key 无权访问的爬虫，
+	// 此时改用 API Key 可以访问的最近一次推送的错误
+	if len(key.Sources) > 0 {
+		status.LastError = ""
+		history, err := store.DeliveryHistory(conf.Name, 1, key.AllowsSource)
+		if err != nil {
+			return BotInfo{}, err
+		}
+		if len(history) > 0 {
+			status.LastError = history[0].Error
+		}
+	}
+	info := BotInfo{
+		Name:        conf.Name,
+		Description: conf.Description,
+		Enabled:     enabled,
+		Schedules:   []string{},
+		Pending:     pending,
+		Delivered:   delivered,
+		RunInfo:     runInfo(status),
+	}
+	if enabled {
+		info.Schedules = scheduler.BotSchedules(conf.Name)
+	}
+	return info, nil
+}
@@ -1,13 +1,29 @@
 package controllers
 
 import (
+	"SecCrawler/api/auth"
+	"SecCrawler/config"
 	"SecCrawler/register"
+	"SecCrawler/scheduler"
+	"SecCrawler/store"
 	"SecCrawler/utils"
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// ArticlesResp 爬虫最近抓取结果，crawledAt 为最近一次成功抓取的时间。
+type ArticlesResp struct {
+	Site        string             `json:"site"`
+	Description string             `json:"description"`
+	CrawledAt   time.Time          `json:"crawledAt"`
+	Articles    []register.Article `json:"articles"`
+}
+
+// GetArticles 返回已保存的最大回溯时间内的文章，不直接抓取站点。
+// refresh=true（需要 trigger 权限）或从未抓取过时先抓取一次，并发的请求共享同一次抓取。
 func GetArticles(c *gin.Context) {
 	siteName := c.Params.ByName("site")
 	crawler, ok := register.GetCrawler(siteName)
@@ -15,11 +31,133 @@ func GetArticles(c *gin.Context) {
 		utils.ErrorStrResp(c, utils.SITE_NOT_FOUND, "The site is not open or does not exist")
 		return
 	}
-	fmt.Printf("[*] api call [%s]\n", crawler.Config().Name)
-	result, err := crawler.Get()
+	name := crawler.Config().Name
+	key := auth.Current(c)
+	if !key.AllowsSource(name) {
+		auth.Deny(c, "The auth key is not allowed to access "+name)
+		return
+	}
+	refresh := c.Query("refresh") == "true"
+	if refresh && !key.Allows(auth.ScopeTrigger) {
+		auth.Deny(c, "The auth key does not have the trigger scope")
+		return
+	}
+	fmt.Printf("[*] api call [%s]\n", name)
+
+	status, err := store.GetCrawlStatus(name)
 	if err != nil {
 		utils.ErrorResp(c, utils.ARTICLE_NOT_FOUND, err)
 		return
 	}
+	if refresh || status.LastRun.IsZero() {
+		if err := scheduler.Refresh(c.Request.Context(), name); err != nil {
+			utils.ErrorResp(c, utils.ARTICLE_NOT_FOUND, err)
+			return
+		}
+		if status, err = store.GetCrawlStatus(name); err != nil {
+			utils.ErrorResp(c, utils.ARTICLE_NOT_FOUND, err)
+			return
+		}
+	}
+
+	articles, err := store.RecentArticles(name, time.Now().Add(-config.Cfg.Crawler.MaxLookback))
+	if err != nil {
+		utils.ErrorResp(c, utils.ARTICLE_NOT_FOUND, err)
+		return
+	}
+	utils.SuccessResp(c, ArticlesResp{
+		Site:        name,
+		Description: crawler.Config().Description,
+		CrawledAt:   status.LastSuccess,
+		Articles:    articles,
+	})
+}
+
+// CrawlerInfo 爬虫的配置与运行状态。
+type CrawlerInfo struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Enabled     bool     `json:"enabled"`
+	Schedule    string   `json:"schedule,omitempty"` // 独立的抓取计划
+	Schedules   []string `json:"schedules"`          // 包含该爬虫的推送计划
+	Items       int      `json:"items"`              // 已保存的文章数量
+	RunInfo
+}
+
+// ListCrawlers 列出当前 API Key 可访问的所有爬虫（包括未启用的）及其运行状态。
+func ListCrawlers(c *gin.Context) {
+	key := auth.Current(c)
+	result := []CrawlerInfo{}
+	for _, crawler := range register.GetCrawlerMap() {
+		if !key.AllowsSource(crawler.Config().Name) {
+			continue
+		}
+		info, err := crawlerInfo(crawler.Config(), true)
+		if err != nil {
+			utils.ErrorResp(c, utils.SITE_NOT_FOUND, err)
+			return
+		}
+		result = append(result, info)
+	}
+	for _, conf := range register.GetDisabledCrawlerMap() {
+		if !key.AllowsSource(conf.Name) {
+			continue
+		}
+		info, err := crawlerInfo(conf, false)
+		if err != nil {
+			utils.ErrorResp(c, utils.SITE_NOT_FOUND, err)
+			return
+		}
+		result = append(result, info)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
 	utils.SuccessResp(c, result)
 }
+
+// GetCrawlerStatus 返回单个爬虫的配置与运行状态。
+func GetCrawlerStatus(c *gin.Context) {
+	siteName := c.Params.ByName("site")
+	conf, enabled := register.CrawlerConfig{}, true
+	if crawler, ok := register.GetCrawler(siteName); ok {
+		conf = crawler.Config()
+	} else if disabled, ok := register.GetDisabledCrawlerMap()[siteName]; ok {
+		conf, enabled = disabled, false
+	} else {
+		utils.ErrorStrResp(c, utils.SITE_NOT_FOUND, "The site does not exist")
+		return
+	}
+	if !auth.Current(c).AllowsSource(conf.Name) {
+		auth.Deny(c, "The auth key is not allowed to access "+conf.Name)
+		return
+	}
+	info, err := crawlerInfo(conf, enabled)
+	if err != nil {
+		utils.ErrorResp(c, utils.SITE_NOT_FOUND, err)
+		return
+	}
+	utils.SuccessResp(c, info)
+}
+
+func crawlerInfo(conf register.CrawlerConfig, enabled bool) (CrawlerInfo, error) {
+	status, err := store.GetCrawlStatus(conf.Name)
+	if err != nil {
+		return CrawlerInfo{}, err
+	}
+	items, err := store.ArticleCount(conf.Name)
+	if err != nil {
+		return CrawlerInfo{}, err
+	}
+	info := CrawlerInfo{
+		Name:        conf.Name,
+		Description: conf.Description,
+		Enabled:     enabled,
+		Schedules:   []string{},
+		Items:       items,
+		RunInfo:     runInfo(status),
+	}
+	if enabled {
+		info.Schedule = scheduler.CrawlerSchedule(conf)
+		info.Schedules = scheduler.CrawlerSchedules(conf.Name)
+	}
+	return info, nil
+}
@@ -0,0 +1,207 @@
+package controllers
+
+import (
+	"SecCrawler/api/auth"
+	"SecCrawler/register"
+	"SecCrawler/store"
+	"SecCrawler/utils"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// feedFormats 支持的订阅格式及对应的编码函数和 Content-Type。
+var feedFormats = map[string]struct {
+	encode      func(feedInfo) ([]byte, error)
+	contentType string
+}{
+	"rss":  {encodeRSS, "application/rss+xml; charset=utf-8"},
+	"atom": {encodeAtom, "application/atom+xml; charset=utf-8"},
+	"json": {encodeJSONFeed, "application/feed+json; charset=utf-8"},
+}
+
+// FeedKey 返回请求对应的订阅源标识：all、crawler/<爬虫名称> 或 tag/<标签>，不区分大小写。
+func FeedKey(c *gin.Context) string {
+	if site := c.Param("site"); site != "" {
+		return "crawler/" + strings.ToLower(site)
+	}
+	if tag := c.Param("tag"); tag != "" {
+		return "tag/" + strings.ToLower(strings.TrimSpace(tag))
+	}
+	return "all"
+}
+
+// GetFeed 将保存的文章生成为 RSS 2.0、Atom 或 JSON Feed 1.1 订阅源，
+// 可按爬虫或标签筛选，limit 为文章数量（默认 50，最大 200）。
+func GetFeed(c *gin.Context) {
+	format, ok := feedFormats[c.Param("format")]
+	if !ok {
+		utils.ErrorStrResp(c, utils.INVALID_PARAMS, "Invalid format, should be rss, atom or json")
+		return
+	}
+	query := store.SearchQuery{Limit: 50}
+	if limit := c.Query("limit"); limit != "" {
+		var err error
+		query.Limit, err = strconv.Atoi(limit)
+		if err != nil || query.Limit <= 0 || query.Limit > 200 {
+			utils.ErrorStrResp(c, utils.INVALID_PARAMS, "Invalid limit, should be 1-200")
+			return
+		}
+	}
+
+	key := auth.Current(c)
+	info := feedInfo{
+		Title:       "SecCrawler",
+		Description: "SecCrawler 抓取的所有安全文章",
+		HomeURL:     baseURL(c) + "/",
+		FeedURL:     feedURL(c),
+	}
+	if site := c.Param("site"); site != "" {
+		conf, ok := crawlerConfig(site)
+		if !ok {
+			utils.ErrorStrResp(c, utils.SITE_NOT_FOUND, "The site does not exist")
+			return
+		}
+		if !key.AllowsSource(conf.Name) {
+			auth.Deny(c, "The auth key is not allowed to access "+conf.Name)
+			return
+		}
+		query.Sources = []string{conf.Name}
+		info.Title = "SecCrawler - " + conf.Name
+		info.Description = conf.Description
+	} else {
+		query.Sources = key.Sources
+	}
+	if tag := strings.TrimSpace(c.Param("tag")); tag != "" {
+		query.Tag = tag
+		info.Title = "SecCrawler - #" + tag
+		info.Description = "SecCrawler 抓取的标签为 " + tag + " 的安全文章"
+	}
+
+	records, _, err := store.Search(query)
+	if err != nil {
+		utils.ErrorResp(c, utils.ARTICLE_NOT_FOUND, err)
+		return
+	}
+	info.Records = records
+	info.Updated = time.Now()
+	if len(records) > 0 {
+		info.Updated = recordTime(records[0])
+	}
+
+	raw, err := format.encode(info)
+	if err != nil {
+		utils.ErrorResp(c, utils.ARTICLE_NOT_FOUND, err)
+		return
+	}
+	c.Data(200, format.contentType, raw)
+}
+
+// FeedLinks 单个订阅源各格式的地址，均已带上当前 API Key 的 token。
+type FeedLinks struct {
+	Name string `json:"name"`
+	RSS  string `json:"rss"`
+	Atom string `json:"atom"`
+	JSON string `json:"json"`
+}
+
+// FeedsResp 所有订阅源的地址。
+type FeedsResp struct {
+	All      FeedLinks   `json:"all"`
+	Crawlers []FeedLinks `json:"crawlers"`
+	Tags     []FeedLinks `json:"tags"`
+}
+
+// ListFeeds 列出综合订阅源和当前 API Key 可访问的每个爬虫的订阅地址，
+// tag 参数（可多个，逗号分隔）指定需要的标签订阅源。
+func ListFeeds(c *gin.Context) {
+	base := baseURL(c) + "/api/feed/"
+	apiKey := auth.Current(c)
+	links := func(name, path, key string) FeedLinks {
+		query := "?token=" + url.QueryEscape(apiKey.FeedToken(key))
+		return FeedLinks{
+			Name: name,
+			RSS:  base + "rss" + path + query,
+			Atom: base + "atom" + path + query,
+			JSON: base + "json" + path + query,
+		}
+	}
+
+	resp := FeedsResp{
+		All:      links("all", "", "all"),
+		Crawlers: []FeedLinks{},
+		Tags:     []FeedLinks{},
+	}
+	var names []string
+	for name := range register.GetCrawlerMap() {
+		if apiKey.AllowsSource(name) {
+			names = append(names, name)
+		}
+	}
+	for name := range register.GetDisabledCrawlerMap() {
+		if apiKey.AllowsSource(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		path := "/crawler/" + url.PathEscape(name)
+		resp.Crawlers = append(resp.Crawlers, links(name, path, "crawler/"+strings.ToLower(name)))
+	}
+	for _, tags := range c.QueryArray("tag") {
+		for _, tag := range strings.Split(tags, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				path := "/tag/" + url.PathEscape(tag)
+				resp.Tags = append(resp.Tags, links(tag, path, "tag/"+strings.ToLower(tag)))
+			}
+		}
+	}
+	utils.SuccessResp(c, resp)
+}
+
+// crawlerConfig 按名称查找爬虫（包括未启用的），不区分大小写。
+func crawlerConfig(name string) (register.CrawlerConfig, bool) {
+	for _, crawler := range register.GetCrawlerMap() {
+		if conf := crawler.Config(); strings.EqualFold(conf.Name, name) {
+			return conf, true
+		}
+	}
+	for _, conf := range register.GetDisabledCrawlerMap() {
+		if strings.EqualFold(conf.Name, name) {
+			return conf, true
+		}
+	}
+	return register.CrawlerConfig{}, false
+}
+
+// baseURL 返回服务的访问地址。请求来自 TrustedProxies 中的反向代理时使用 X-Forwarded-Proto 和
+// X-Forwarded-Host，其他客户端发送的这两个头会被忽略，避免订阅源链接和带 token 的订阅地址指向伪造的域名。
+func baseURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	host := c.Request.Host
+	if _, trusted := c.RemoteIP(); trusted {
+		if proto := c.GetHeader("X-Forwarded-Proto"); proto == "http" || proto == "https" {
+			scheme = proto
+		}
+		if forwarded := c.GetHeader("X-Forwarded-Host"); forwarded != "" {
+			host = forwarded
+		}
+	}
+	return scheme + "://" + host
+}
+
+// feedURL 返回当前订阅源的地址，保留 token 参数以便阅读器使用 self 链接。
+func feedURL(c *gin.Context) string {
+	u := baseURL(c) + c.Request.URL.EscapedPath()
+	if token := c.Query("token"); token != "" {
+		u += "?token=" + url.QueryEscape(token)
+	}
+	return u
+}
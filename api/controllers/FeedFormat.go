@@ -0,0 +1,225 @@
+package controllers
+
+import (
+	"SecCrawler/store"
+	"encoding/json"
+	"encoding/xml"
+	"time"
+)
+
+// feedInfo 订阅源的基本信息，由各格式的编码函数共用。
+type feedInfo struct {
+	Title       string
+	Description string
+	HomeURL     string // 服务地址
+	FeedURL     string // 订阅源自身的地址，也作为 Atom 的 id
+	Updated     time.Time
+	Records     []store.Record
+}
+
+// recordTime 返回文章的发布时间，站点未提供时为首次抓取时间。
+func recordTime(record store.Record) time.Time {
+	if record.Published.IsZero() {
+		return record.FirstSeen
+	}
+	return record.Published
+}
+
+// recordContent 返回文章的正文内容，没有摘要时使用标题。
+func recordContent(record store.Record) string {
+	if record.Summary != "" {
+		return record.Summary
+	}
+	return record.Title
+}
+
+// recordTags 返回文章的分类，包含来源爬虫名称。
+func recordTags(record store.Record) []string {
+	return append([]string{record.Crawler}, record.Tags...)
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Atom    string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Generator     string    `xml:"generator"`
+	Self          atomLink  `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	GUID        rssGUID  `xml:"guid"`
+	PubDate     string   `xml:"pubDate"`
+	Categories  []string `xml:"category"`
+	Description string   `xml:"description,omitempty"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// encodeRSS 生成 RSS 2.0 订阅源。
+func encodeRSS(info feedInfo) ([]byte, error) {
+	channel := rssChannel{
+		Title:         info.Title,
+		Link:          info.HomeURL,
+		Description:   info.Description,
+		LastBuildDate: info.Updated.Format(time.RFC1123Z),
+		Generator:     "SecCrawler",
+		Self:          atomLink{Href: info.FeedURL, Rel: "self", Type: "application/rss+xml"},
+		Items:         []rssItem{},
+	}
+	for _, record := range info.Records {
+		channel.Items = append(channel.Items, rssItem{
+			Title:       record.Title,
+			Link:        record.URL,
+			GUID:        rssGUID{IsPermaLink: true, Value: record.URL},
+			PubDate:     recordTime(record).Format(time.RFC1123Z),
+			Categories:  recordTags(record),
+			Description: record.Summary,
+		})
+	}
+	return marshalXML(rssFeed{Version: "2.0", Atom: "http://www.w3.org/2005/Atom", Channel: channel})
+}
+
+type atomFeed struct {
+	XMLName   xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title     string      `xml:"title"`
+	Subtitle  string      `xml:"subtitle,omitempty"`
+	ID        string      `xml:"id"`
+	Updated   string      `xml:"updated"`
+	Generator string      `xml:"generator"`
+	Links     []atomLink  `xml:"link"`
+	Entries   []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	Title      string         `xml:"title"`
+	ID         string         `xml:"id"`
+	Link       atomLink       `xml:"link"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Author     *atomAuthor    `xml:"author,omitempty"`
+	Categories []atomCategory `xml:"category"`
+	Summary    string         `xml:"summary,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// encodeAtom 生成 Atom 订阅源。
+func encodeAtom(info feedInfo) ([]byte, error) {
+	feed := atomFeed{
+		Title:     info.Title,
+		Subtitle:  info.Description,
+		ID:        info.FeedURL,
+		Updated:   info.Updated.Format(time.RFC3339),
+		Generator: "SecCrawler",
+		Links: []atomLink{
+			{Href: info.FeedURL, Rel: "self", Type: "application/atom+xml"},
+			{Href: info.HomeURL, Rel: "alternate"},
+		},
+		Entries: []atomEntry{},
+	}
+	for _, record := range info.Records {
+		published := recordTime(record).Format(time.RFC3339)
+		entry := atomEntry{
+			Title:     record.Title,
+			ID:        record.URL,
+			Link:      atomLink{Href: record.URL, Rel: "alternate"},
+			Published: published,
+			Updated:   published,
+			Summary:   record.Summary,
+		}
+		if record.Author != "" {
+			entry.Author = &atomAuthor{Name: record.Author}
+		}
+		for _, tag := range recordTags(record) {
+			entry.Categories = append(entry.Categories, atomCategory{Term: tag})
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+	return marshalXML(feed)
+}
+
+func marshalXML(v interface{}) ([]byte, error) {
+	raw, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), raw...), nil
+}
+
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	Description string         `json:"description,omitempty"`
+	HomePageURL string         `json:"home_page_url"`
+	FeedURL     string         `json:"feed_url"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            string           `json:"id"`
+	URL           string           `json:"url"`
+	Title         string           `json:"title"`
+	ContentText   string           `json:"content_text"`
+	Summary       string           `json:"summary,omitempty"`
+	DatePublished string           `json:"date_published"`
+	Authors       []jsonFeedAuthor `json:"authors,omitempty"`
+	Tags          []string         `json:"tags"`
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+}
+
+// encodeJSONFeed 生成 JSON Feed 1.1 订阅源。
+func encodeJSONFeed(info feedInfo) ([]byte, error) {
+	feed := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       info.Title,
+		Description: info.Description,
+		HomePageURL: info.HomeURL,
+		FeedURL:     info.FeedURL,
+		Items:       []jsonFeedItem{},
+	}
+	for _, record := range info.Records {
+		item := jsonFeedItem{
+			ID:            record.URL,
+			URL:           record.URL,
+			Title:         record.Title,
+			ContentText:   recordContent(record),
+			Summary:       record.Summary,
+			DatePublished: recordTime(record).Format(time.RFC3339),
+			Tags:          recordTags(record),
+		}
+		if record.Author != "" {
+			item.Authors = []jsonFeedAuthor{{Name: record.Author}}
+		}
+		feed.Items = append(feed.Items, item)
+	}
+	return json.MarshalIndent(feed, "", "  ")
+}
@@ -0,0 +1,80 @@
+package controllers
+
+import (
+	"SecCrawler/api/auth"
+	"SecCrawler/scheduler"
+	"SecCrawler/utils"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JobReq 手动任务的参数，crawlers 为空表示全部已启用的爬虫。
+// push 为 true 或指定了 bots 时抓取后推送，bots 为空表示全部已启用的Bot；
+// resend 为 true 时先将今天已推送的文章重新入队，用于重发日报，push 不为 true 时只重发不抓取。
+type JobReq struct {
+	Crawlers []string `json:"crawlers"`
+	Bots     []string `json:"bots"`
+	Push     bool     `json:"push"`
+	Resend   bool     `json:"resend"`
+}
+
+// RunJob 在后台立即抓取并可选推送，返回任务 ID 和初始状态，之后通过 GetJobStatus 查询进度。
+func RunJob(c *gin.Context) {
+	var req JobReq
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		utils.ErrorStrResp(c, utils.INVALID_PARAMS, "Invalid params: "+err.Error())
+		return
+	}
+	crawlers, denied, ok := auth.Current(c).RestrictSources(req.Crawlers)
+	if !ok {
+		auth.Deny(c, "The auth key is not allowed to access "+denied)
+		return
+	}
+	job, err := scheduler.Trigger(scheduler.JobOptions{
+		Crawlers: crawlers,
+		Bots:     req.Bots,
+		Push:     req.Push || (len(req.Bots) > 0 && !req.Resend),
+		Resend:   req.Resend,
+	})
+	if err != nil {
+		utils.ErrorResp(c, utils.INVALID_PARAMS, err)
+		return
+	}
+	fmt.Printf("[*] api run job [%s]\n", job.ID)
+	utils.SuccessResp(c, job)
+}
+
+// GetJobStatus 返回任务的进度以及每个爬虫、Bot的结果，API Key 无权访问任务中的爬虫时视为任务不存在。
+func GetJobStatus(c *gin.Context) {
+	job, ok := scheduler.GetJob(c.Param("id"))
+	if !ok || !jobVisible(auth.Current(c), job) {
+		utils.ErrorStrResp(c, utils.JOB_NOT_FOUND, "The job does not exist")
+		return
+	}
+	utils.SuccessResp(c, job)
+}
+
+// ListJobs 列出当前 API Key 可以访问的最近的手动任务，按创建时间从新到旧排列。
+func ListJobs(c *gin.Context) {
+	key := auth.Current(c)
+	jobs := []scheduler.Job{}
+	for _, job := range scheduler.Jobs() {
+		if jobVisible(key, job) {
+			jobs = append(jobs, job)
+		}
+	}
+	utils.SuccessResp(c, jobs)
+}
+
+// jobVisible 判断 API Key 是否可以访问任务中的所有爬虫，Bot的错误信息中也包含爬虫名称，因此不只隐藏部分爬虫。
+func jobVisible(key *auth.Key, job scheduler.Job) bool {
+	for name := range job.Crawlers {
+		if !key.AllowsSource(name) {
+			return false
+		}
+	}
+	return true
+}
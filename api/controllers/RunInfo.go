@@ -0,0 +1,36 @@
+package controllers
+
+import (
+	"SecCrawler/store"
+	"time"
+)
+
+// RunInfo 爬虫或Bot的运行状态，从未运行时时间字段为空。
+type RunInfo struct {
+	LastRun           *time.Time `json:"lastRun"`
+	LastSuccess       *time.Time `json:"lastSuccess"`
+	LastError         string     `json:"lastError,omitempty"`
+	LastDurationMs    int64      `json:"lastDurationMs"`
+	AverageDurationMs int64      `json:"averageDurationMs"`
+	Runs              int        `json:"runs"`
+	Failures          int        `json:"failures"`
+}
+
+func runInfo(status store.RunStatus) RunInfo {
+	return RunInfo{
+		LastRun:           timeOrNil(status.LastRun),
+		LastSuccess:       timeOrNil(status.LastSuccess),
+		LastError:         status.LastError,
+		LastDurationMs:    status.LastDuration.Milliseconds(),
+		AverageDurationMs: status.AverageDuration().Milliseconds(),
+		Runs:              status.Runs,
+		Failures:          status.Failures,
+	}
+}
+
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,232 @@
+package controllers
+
+import (
+	"SecCrawler/api/auth"
+	. "SecCrawler/config"
+	"SecCrawler/store"
+	"SecCrawler/utils"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	streamBatch     = 100
+	streamHeartbeat = 30 * time.Second
+)
+
+// streamFilter 实时推送的筛选条件，零值字段不参与过滤。
+type streamFilter struct {
+	sources []string // 爬虫名称，不区分大小写
+	words   []string // 标题或摘要中需同时包含的关键词，已转为小写
+}
+
+func (filter streamFilter) match(record store.Record) bool {
+	if len(filter.sources) > 0 {
+		matched := false
+		for _, source := range filter.sources {
+			if strings.EqualFold(source, record.Crawler) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	text := strings.ToLower(record.Title + " " + record.Summary)
+	for _, word := range filter.words {
+		if !strings.Contains(text, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// streamParams 解析筛选条件和续传位置。续传位置取自 Last-Event-ID 头或 lastEventId 参数，
+// 都没有时只推送之后发现的文章。
+func streamParams(c *gin.Context) (uint64, streamFilter, bool) {
+	filter := streamFilter{words: strings.Fields(strings.ToLower(c.Query("q")))}
+	for _, source := range c.QueryArray("source") {
+		for _, name := range strings.Split(source, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				filter.sources = append(filter.sources, name)
+			}
+		}
+	}
+
+	sources, denied, ok := auth.Current(c).RestrictSources(filter.sources)
+	if !ok {
+		auth.Deny(c, "The auth key is not allowed to access "+denied)
+		return 0, filter, false
+	}
+	filter.sources = sources
+
+	lastID := c.GetHeader("Last-Event-ID")
+	if lastID == "" {
+		lastID = c.Query("lastEventId")
+	}
+	if lastID == "" {
+		after, err := store.LastEventID()
+		if err != nil {
+			utils.ErrorResp(c, utils.ARTICLE_NOT_FOUND, err)
+			return 0, filter, false
+		}
+		return after, filter, true
+	}
+	after, err := strconv.ParseUint(lastID, 10, 64)
+	if err != nil {
+		utils.ErrorStrResp(c, utils.INVALID_PARAMS, "Invalid Last-Event-ID")
+		return 0, filter, false
+	}
+	return after, filter, true
+}
+
+// streamEvents 持续读取序号大于 after 的事件，将符合条件的事件交给 send，
+// 空闲时定期调用 ping 保持连接，直到 ctx 取消或发送失败。
+func streamEvents(ctx context.Context, after uint64, filter streamFilter, send func(store.Event) error, ping func() error) error {
+	// 先订阅再读取，避免读取与订阅之间保存的文章被遗漏
+	notified, cancel := store.Subscribe()
+	defer cancel()
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		events, err := store.Events(after, streamBatch)
+		if err != nil {
+			return err
+		}
+		for _, event := range events {
+			after = event.ID
+			if !filter.match(event.Record) {
+				continue
+			}
+			if err := send(event); err != nil {
+				return err
+			}
+		}
+		if len(events) == streamBatch {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-notified:
+		case <-heartbeat.C:
+			if err := ping(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// StreamArticles 以 Server-Sent Events 实时推送新发现的文章，每条事件的 id 为事件序号，
+// data 为文章的 JSON。参数：source 爬虫名称（可多个，逗号分隔）、q 关键词。
+func StreamArticles(c *gin.Context) {
+	after, filter, ok := streamParams(c)
+	if !ok {
+		return
+	}
+	fmt.Printf("[*] stream connected [%s]\n", c.ClientIP())
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	w := c.Writer
+	fmt.Fprint(w, "retry: 5000\n\n")
+	w.Flush()
+
+	send := func(event store.Event) error {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "id: %d\nevent: article\ndata: %s\n\n", event.ID, data); err != nil {
+			return err
+		}
+		w.Flush()
+		return nil
+	}
+	ping := func() error {
+		if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+			return err
+		}
+		w.Flush()
+		return nil
+	}
+	if err := streamEvents(c.Request.Context(), after, filter, send, ping); err != nil {
+		log.Printf("stream [%s] error: %s\n", c.ClientIP(), err.Error())
+	}
+}
+
+var upgrader = websocket.Upgrader{CheckOrigin: checkOrigin}
+
+// checkOrigin 校验 WebSocket 握手的来源。浏览器发起握手时无法设置 Authorization 头，
+// 匿名模式或使用 token 参数时任意网页都能建立连接，因此只允许同源和 CorsOrigins 中的来源，
+// CorsOrigins 留空时只允许同源，包含 * 时允许全部。没有 Origin 头的请求不是来自浏览器，不做限制。
+func checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+	for _, allowed := range Cfg.Api.CorsOrigins {
+		if allowed == "*" || strings.EqualFold(strings.TrimRight(allowed, "/"), origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// StreamArticlesWS 以 WebSocket 实时推送新发现的文章，每条消息为包含事件序号 id 的文章 JSON，
+// 参数与 StreamArticles 相同，续传时使用 lastEventId 参数。
+func StreamArticlesWS(c *gin.Context) {
+	after, filter, ok := streamParams(c)
+	if !ok {
+		return
+	}
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	fmt.Printf("[*] websocket stream connected [%s]\n", c.ClientIP())
+
+	// 读取并丢弃客户端消息，连接关闭时结束推送
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	send := func(event store.Event) error {
+		conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+		return conn.WriteJSON(event)
+	}
+	ping := func() error {
+		return conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(10*time.Second))
+	}
+	if err := streamEvents(ctx, after, filter, send, ping); err != nil && ctx.Err() == nil {
+		log.Printf("websocket stream [%s] error: %s\n", c.ClientIP(), err.Error())
+	}
+	conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, ""), time.Now().Add(time.Second))
+}
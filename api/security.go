@@ -0,0 +1,196 @@
+package api
+
+import (
+	"SecCrawler/api/auth"
+	"SecCrawler/config"
+	"SecCrawler/utils"
+	"fmt"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/time/rate"
+)
+
+func setCors(r *gin.Engine) {
+	conf := cors.DefaultConfig()
+	origins := config.Cfg.Api.CorsOrigins
+	if len(origins) == 0 || contains(origins, "*") {
+		conf.AllowAllOrigins = true
+	} else {
+		conf.AllowOrigins = origins
+	}
+	conf.AllowHeaders = append(conf.AllowHeaders, "Authorization", "Last-Event-ID")
+	if err := conf.Validate(); err != nil {
+		log.Fatalf("api CorsOrigins error: %s\n", err.Error())
+	}
+	r.Use(cors.New(conf))
+}
+
+// setTrustedProxies 只信任来自反向代理的 X-Forwarded-For，未配置时使用连接的来源地址，
+// 避免客户端伪造 IP 绕过访问控制和频率限制。
+func setTrustedProxies(r *gin.Engine) {
+	if err := r.SetTrustedProxies(config.Cfg.Api.TrustedProxies); err != nil {
+		log.Fatalf("api TrustedProxies error: %s\n", err.Error())
+	}
+}
+
+// parseNets 解析 IP 或 CIDR 列表，单个 IP 视为只包含该地址的网段。
+func parseNets(values []string) ([]*net.IPNet, error) {
+	var nets []*net.IPNet
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if !strings.Contains(value, "/") {
+			ip := net.ParseIP(value)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid ip [%s]", value)
+			}
+			bits := 32
+			if ip.To4() == nil {
+				bits = 128
+			}
+			value = fmt.Sprintf("%s/%d", value, bits)
+		}
+		_, ipNet, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cidr [%s]", value)
+		}
+		nets = append(nets, ipNet)
+	}
+	return nets, nil
+}
+
+func containsIP(nets []*net.IPNet, ip net.IP) bool {
+	for _, ipNet := range nets {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ipFilter 按 allow/deny 列表限制访问来源，deny 优先，allow 为空表示允许全部。
+func ipFilter() gin.HandlerFunc {
+	allow, err := parseNets(config.Cfg.Api.Allow)
+	if err != nil {
+		log.Fatalf("api allow error: %s\n", err.Error())
+	}
+	deny, err := parseNets(config.Cfg.Api.Deny)
+	if err != nil {
+		log.Fatalf("api deny error: %s\n", err.Error())
+	}
+	return func(c *gin.Context) {
+		ip := net.ParseIP(c.ClientIP())
+		if ip == nil || containsIP(deny, ip) || (len(allow) > 0 && !containsIP(allow, ip)) {
+			utils.ErrorStatusResp(c, http.StatusForbidden, utils.PERMISSION_DENIED, "Your IP is not allowed")
+			return
+		}
+		c.Next()
+	}
+}
+
+// rateLimiter 按客户端（IP 或 API Key）分别限制请求频率。
+type rateLimiter struct {
+	limit rate.Limit
+	burst int
+
+	mu      sync.Mutex
+	clients map[string]*rateClient
+	cleaned time.Time
+}
+
+type rateClient struct {
+	limiter *rate.Limiter
+	seen    time.Time
+}
+
+// rateIdle 超过该时间没有请求的客户端会被清理。
+const rateIdle = 10 * time.Minute
+
+// newRateLimiter 每分钟允许 perMinute 个请求，perMinute 不大于 0 时返回 nil 表示不限制。
+func newRateLimiter(perMinute, burst int) *rateLimiter {
+	if perMinute <= 0 {
+		return nil
+	}
+	if burst <= 0 {
+		burst = perMinute
+	}
+	return &rateLimiter{
+		limit:   rate.Limit(float64(perMinute) / 60),
+		burst:   burst,
+		clients: map[string]*rateClient{},
+		cleaned: time.Now(),
+	}
+}
+
+// allow 判断客户端是否可以发出请求，不可以时返回需要等待的时间。
+func (l *rateLimiter) allow(id string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	if now.Sub(l.cleaned) > rateIdle {
+		for key, client := range l.clients {
+			if now.Sub(client.seen) > rateIdle {
+				delete(l.clients, key)
+			}
+		}
+		l.cleaned = now
+	}
+
+	client, ok := l.clients[id]
+	if !ok {
+		client = &rateClient{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.clients[id] = client
+	}
+	client.seen = now
+	reservation := client.limiter.ReserveN(now, 1)
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		return false, delay
+	}
+	return true, 0
+}
+
+// rateLimit 返回频率限制中间件，id 返回客户端标识。
+func rateLimit(l *rateLimiter, id func(*gin.Context) string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if l == nil {
+			c.Next()
+			return
+		}
+		if ok, delay := l.allow(id(c)); !ok {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+			utils.ErrorStatusResp(c, http.StatusTooManyRequests, utils.TOO_MANY_REQUESTS, "Too many requests")
+			return
+		}
+		c.Next()
+	}
+}
+
+// ipRateLimit 按客户端 IP 限制请求频率，在鉴权之前执行，以限制猜测密钥的速度。
+func ipRateLimit() gin.HandlerFunc {
+	l := newRateLimiter(config.Cfg.Api.RateLimit.PerIP, config.Cfg.Api.RateLimit.Burst)
+	return rateLimit(l, func(c *gin.Context) string { return c.ClientIP() })
+}
+
+// keyRateLimit 按 API Key 限制请求频率，需在鉴权之后执行，多个路由组共用同一个限制。
+func keyRateLimit() gin.HandlerFunc {
+	l := newRateLimiter(config.Cfg.Api.RateLimit.PerKey, config.Cfg.Api.RateLimit.Burst)
+	return rateLimit(l, func(c *gin.Context) string { return auth.Current(c).Name })
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if strings.TrimSpace(v) == value {
+			return true
+		}
+	}
+	return false
+}
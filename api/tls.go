@@ -0,0 +1,102 @@
+package api
+
+import (
+	"SecCrawler/config"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"log"
+	"math/big"
+	"net"
+	"os"
+	"time"
+)
+
+// TLSConfig 检查证书文件并返回 HTTPS 配置，证书文件不存在且开启了 SelfSigned 时生成自签名证书。
+func TLSConfig() *tls.Config {
+	conf := config.Cfg.Api.TLS
+	_, certErr := os.Stat(conf.Cert)
+	_, keyErr := os.Stat(conf.Key)
+	if os.IsNotExist(certErr) && os.IsNotExist(keyErr) && conf.SelfSigned {
+		hosts := conf.Hosts
+		if len(hosts) == 0 {
+			hosts = []string{config.Cfg.Api.Host, "localhost", "127.0.0.1"}
+		}
+		if err := generateCert(conf.Cert, conf.Key, hosts); err != nil {
+			log.Fatalf("generate self-signed certificate error: %s\n", err.Error())
+		}
+		fmt.Printf("[*] generate self-signed certificate [%s] for %v\n", conf.Cert, hosts)
+	}
+
+	cert, err := tls.LoadX509KeyPair(conf.Cert, conf.Key)
+	if err != nil {
+		log.Fatalf("load certificate error: %s\n", err.Error())
+	}
+	return &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+	}
+}
+
+// generateCert 生成有效期一年的 ECDSA 自签名证书。
+func generateCert(certFile, keyFile string, hosts []string) error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return err
+	}
+	template := x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{Organization: []string{"SecCrawler"}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().AddDate(1, 0, 0),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+	}
+	seen := map[string]bool{}
+	for _, host := range hosts {
+		if seen[host] {
+			continue
+		}
+		seen[host] = true
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else if host != "" {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		return err
+	}
+	keyDer, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return err
+	}
+	if err := writePEM(certFile, "CERTIFICATE", der, 0644); err != nil {
+		return err
+	}
+	return writePEM(keyFile, "PRIVATE KEY", keyDer, 0600)
+}
+
+func writePEM(path, blockType string, der []byte, perm os.FileMode) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	if err := pem.Encode(f, &pem.Block{Type: blockType, Bytes: der}); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,243 @@
+'use strict';
+
+// 管理页面挂载在 /dashboard/ 下，API 使用相对地址以便经反向代理访问
+const API = new URL('../api/', location.href);
+const AUTH_KEY = 'seccrawler.auth';
+const REFRESH_INTERVAL = 60 * 1000;
+
+function escapeHTML(value) {
+  return String(value == null ? '' : value).replace(/[&<>"']/g, c => ({
+    '&': '&amp;', '<': '&lt;', '>': '&gt;', '"': '&quot;', "'": '&#39;',
+  })[c]);
+}
+
+// safeURL 只允许 http 和 https 链接。文章链接来自抓取的第三方内容，
+// javascript: 等链接会在页面中执行脚本并读取保存的 API Key。
+function safeURL(value) {
+  try {
+    const url = new URL(value);
+    return url.protocol === 'http:' || url.protocol === 'https:' ? url.href : '';
+  } catch (err) {
+    return '';
+  }
+}
+
+function articleLink(article) {
+  const url = safeURL(article.url);
+  if (!url) {
+    return escapeHTML(article.title);
+  }
+  return `<a href="${escapeHTML(url)}" target="_blank" rel="noopener noreferrer">${escapeHTML(article.title)}</a>`;
+}
+
+function formatTime(value) {
+  if (!value || value.startsWith('0001-')) {
+    return '-';
+  }
+  return new Date(value).toLocaleString();
+}
+
+function formatDuration(ms) {
+  return ms ? (ms / 1000).toFixed(1) + 's' : '-';
+}
+
+function showMessage(text, failed) {
+  const message = document.getElementById('message');
+  message.textContent = text;
+  message.className = failed ? 'fail' : '';
+  message.hidden = false;
+}
+
+async function request(path, options = {}) {
+  const headers = { 'Authorization': localStorage.getItem(AUTH_KEY) || '' };
+  if (options.body) {
+    headers['Content-Type'] = 'application/json';
+  }
+  const resp = await fetch(new URL(path, API), { ...options, headers });
+  const body = await resp.json();
+  if (body.code !== 200) {
+    throw new Error(body.msg);
+  }
+  return body.data;
+}
+
+// loadArticles 按爬虫分组显示今天发布或抓取的文章。
+async function loadArticles() {
+  const today = new Date();
+  today.setHours(0, 0, 0, 0);
+  const articles = [];
+  let cursor = '';
+  // 最多读取 10 页，避免文章过多时页面卡顿
+  for (let page = 0; page < 10; page++) {
+    const params = new URLSearchParams({ from: today.toISOString(), limit: '100' });
+    if (cursor) {
+      params.set('cursor', cursor);
+    }
+    const data = await request('article/search?' + params);
+    articles.push(...data.articles);
+    cursor = data.nextCursor;
+    if (!cursor) {
+      break;
+    }
+  }
+
+  const groups = new Map();
+  for (const article of articles) {
+    if (!groups.has(article.crawler)) {
+      groups.set(article.crawler, []);
+    }
+    groups.get(article.crawler).push(article);
+  }
+  document.getElementById('article-count').textContent = `共 ${articles.length} 篇`;
+  const container = document.getElementById('articles');
+  if (groups.size === 0) {
+    container.innerHTML = '<p class="empty">今天还没有新文章</p>';
+    return;
+  }
+  container.innerHTML = [...groups.keys()].sort().map(crawler => `
+    <h3>${escapeHTML(crawler)} <small class="meta">${groups.get(crawler).length}</small></h3>
+    <ul>${groups.get(crawler).map(article => `
+      <li>
+        ${articleLink(article)}
+        <span class="meta">${escapeHTML(formatTime(article.published || article.firstSeen))}</span>
+      </li>`).join('')}
+    </ul>`).join('');
+}
+
+function statusCell(item) {
+  if (!item.enabled) {
+    return '<span class="off">未启用</span>';
+  }
+  if (!item.lastRun) {
+    return '<span class="meta">未运行</span>';
+  }
+  return item.lastError ? '<span class="fail">失败</span>' : '<span class="ok">正常</span>';
+}
+
+// loadCrawlers 显示每个爬虫的运行状态。
+async function loadCrawlers() {
+  const crawlers = await request('crawler/list');
+  document.getElementById('crawlers').innerHTML = crawlers.map(crawler => `
+    <tr>
+      <td><strong>${escapeHTML(crawler.name)}</strong><br><span class="meta">${escapeHTML(crawler.description)}</span></td>
+      <td>${statusCell(crawler)}</td>
+      <td>${crawler.items}</td>
+      <td>${escapeHTML(formatTime(crawler.lastRun))}</td>
+      <td>${escapeHTML(formatTime(crawler.lastSuccess))}</td>
+      <td>${formatDuration(crawler.averageDurationMs)}</td>
+      <td>${crawler.runs} / ${crawler.failures}</td>
+      <td class="error">${escapeHTML(crawler.lastError)}</td>
+      <td>${crawler.enabled ? `<button data-crawler="${escapeHTML(crawler.name)}">重新抓取</button>` : ''}</td>
+    </tr>`).join('');
+}
+
+// loadBots 显示每个Bot的推送状态和最近的推送记录。
+async function loadBots() {
+  const bots = await request('bot/list');
+  const histories = await Promise.all(bots.map(bot =>
+    request(`bot/history/${encodeURIComponent(bot.name)}?limit=20`)));
+  document.getElementById('bots').innerHTML = bots.map((bot, i) => `
+    <div class="bot">
+      <div class="bot-head">
+        <strong>${escapeHTML(bot.name)}</strong>
+        <span class="meta">${escapeHTML(bot.description)}</span>
+        ${statusCell(bot)}
+        <span class="meta">待推送 ${bot.pending} · 已推送 ${bot.delivered} · 最近推送 ${escapeHTML(formatTime(bot.lastRun))}</span>
+        ${bot.enabled ? `<button data-bot="${escapeHTML(bot.name)}">重发日报</button>` : ''}
+      </div>
+      ${bot.lastError ? `<div class="error">${escapeHTML(bot.lastError)}</div>` : ''}
+      <details>
+        <summary>最近 ${histories[i].length} 次推送</summary>
+        <table>
+          <thead><tr><th>时间</th><th>爬虫</th><th>文章数</th><th>耗时</th><th>结果</th></tr></thead>
+          <tbody>${histories[i].map(record => `
+            <tr>
+              <td>${escapeHTML(formatTime(record.time))}</td>
+              <td>${escapeHTML(record.crawler)}</td>
+              <td>${record.articles}</td>
+              <td>${formatDuration(record.durationMs)}</td>
+              <td>${record.error ? `<span class="error">${escapeHTML(record.error)}</span>` : '<span class="ok">成功</span>'}</td>
+            </tr>`).join('')}
+          </tbody>
+        </table>
+      </details>
+    </div>`).join('') || '<p class="empty">没有Bot</p>';
+}
+
+function jobSummary(results) {
+  return Object.keys(results).sort().map(name => {
+    const result = results[name];
+    const failed = result.status === 'failed';
+    const detail = 'new' in result ? `新 ${result.new}` : `推送 ${result.sent}`;
+    const errors = result.error || (result.errors || []).join('; ');
+    return `<span class="${failed ? 'fail' : ''}" title="${escapeHTML(errors)}">${escapeHTML(name)}: ${escapeHTML(result.status)}, ${detail}</span>`;
+  }).join('<br>') || '-';
+}
+
+// loadJobs 显示最近的手动任务。
+async function loadJobs() {
+  const jobs = await request('job/list');
+  document.getElementById('jobs').innerHTML = jobs.slice(0, 20).map(job => `
+    <tr>
+      <td><code>${escapeHTML(job.id)}</code>${job.resend ? ' <span class="meta">重发</span>' : ''}</td>
+      <td>${escapeHTML(formatTime(job.createdAt))}</td>
+      <td>${escapeHTML(job.status)}</td>
+      <td>${jobSummary(job.crawlers)}</td>
+      <td>${jobSummary(job.bots)}</td>
+    </tr>`).join('') || '<tr><td colspan="5" class="empty">没有任务</td></tr>';
+}
+
+async function refresh() {
+  try {
+    await Promise.all([loadArticles(), loadCrawlers(), loadBots(), loadJobs()]);
+    document.getElementById('updated').textContent = '更新于 ' + new Date().toLocaleTimeString();
+  } catch (err) {
+    showMessage('加载失败：' + err.message, true);
+  }
+}
+
+// runJob 触发手动任务，并轮询到任务完成后刷新页面数据。
+async function runJob(button, params, label) {
+  button.disabled = true;
+  try {
+    let job = await request('job/run', { method: 'POST', body: JSON.stringify(params) });
+    showMessage(`${label}：任务 ${job.id} 已开始`);
+    await loadJobs();
+    while (job.status !== 'done') {
+      await new Promise(resolve => setTimeout(resolve, 2000));
+      job = await request('job/status/' + job.id);
+    }
+    const failed = [...Object.values(job.crawlers), ...Object.values(job.bots)].some(r => r.status === 'failed');
+    showMessage(`${label}：任务 ${job.id} 已完成${failed ? '，部分失败' : ''}`, failed);
+    await refresh();
+  } catch (err) {
+    showMessage(`${label}失败：${err.message}`, true);
+  } finally {
+    button.disabled = false;
+  }
+}
+
+document.addEventListener('click', event => {
+  const button = event.target.closest('button');
+  if (!button) {
+    return;
+  }
+  if (button.dataset.crawler) {
+    runJob(button, { crawlers: [button.dataset.crawler] }, `抓取 ${button.dataset.crawler}`);
+  } else if (button.dataset.bot) {
+    if (confirm(`将今天已推送的文章重新推送给 ${button.dataset.bot}？`)) {
+      runJob(button, { bots: [button.dataset.bot], resend: true }, `重发 ${button.dataset.bot}`);
+    }
+  }
+});
+
+document.getElementById('auth-form').addEventListener('submit', event => {
+  event.preventDefault();
+  localStorage.setItem(AUTH_KEY, document.getElementById('auth').value);
+  document.getElementById('message').hidden = true;
+  refresh();
+});
+
+document.getElementById('auth').value = localStorage.getItem(AUTH_KEY) || '';
+refresh();
+setInterval(refresh, REFRESH_INTERVAL);
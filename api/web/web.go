@@ -0,0 +1,19 @@
+package web
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+//go:embed static
+var static embed.FS
+
+// FS 返回内置的管理页面文件。
+func FS() http.FileSystem {
+	sub, err := fs.Sub(static, "static")
+	if err != nil {
+		panic(err)
+	}
+	return http.FS(sub)
+}
@@ -0,0 +1,200 @@
+package bot
+
+import (
+	. "SecCrawler/config"
+	"SecCrawler/register"
+	"SecCrawler/utils"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+// Discord 消息限制，超出时分页发送。
+// https://discord.com/developers/docs/resources/message#embed-object-embed-limits
+const (
+	discordMaxEmbeds      = 10   // 单条消息的 embed 数量
+	discordMaxEmbedChars  = 6000 // 单条消息所有 embed 的文本总长度
+	discordMaxTitle       = 256
+	discordMaxDescription = 300 // 摘要只展示开头部分，官方限制为 4096
+	discordMaxContent     = 2000
+)
+
+const (
+	discordMaxRetries = 5                // 遇到 429 时的最大重试次数
+	discordMaxWait    = 60 * time.Second // 单次等待超过该时间时不再重试
+	discordColor      = 0x5865F2
+)
+
+type DiscordBot struct{}
+
+// DiscordEmbed Discord embed 对象，每篇文章一个。
+type DiscordEmbed struct {
+	Title       string         `json:"title"`
+	URL         string         `json:"url,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Timestamp   string         `json:"timestamp,omitempty"`
+	Color       int            `json:"color"`
+	Footer      *DiscordFooter `json:"footer,omitempty"`
+}
+
+type DiscordFooter struct {
+	Text string `json:"text"`
+}
+
+// DiscordMessage webhook 消息结构。
+type DiscordMessage struct {
+	Content   string         `json:"content,omitempty"`
+	Username  string         `json:"username,omitempty"`
+	AvatarURL string         `json:"avatar_url,omitempty"`
+	Embeds    []DiscordEmbed `json:"embeds"`
+}
+
+// DiscordRateLimit 429 响应，retry_after 单位为秒。
+type DiscordRateLimit struct {
+	Message    string  `json:"message"`
+	RetryAfter float64 `json:"retry_after"`
+	Global     bool    `json:"global"`
+}
+
+func (bot DiscordBot) Config() register.BotConfig {
+	return register.BotConfig{
+		Name:        "DiscordBot",
+		Description: "Discord机器人",
+	}
+}
+
+// Send 以 embed 形式推送文章，超出 Discord 的数量或长度限制时分页发送。
+func (bot DiscordBot) Send(articles []register.Article, description string) error {
+	return bot.SendContext(context.Background(), articles, description)
+}
+
+// SendContext 与 Send 相同，ctx 被取消时中断正在发送的请求和限流等待。某一页发送失败时停止，
+// 只将此前成功发送的页中的文章报告为已推送，下次从失败的页继续。
+func (bot DiscordBot) SendContext(ctx context.Context, articles []register.Article, description string) error {
+	conf := Cfg.Bot.DiscordBot
+	if conf.Webhook == "" {
+		return errors.New("DiscordBot webhook is not configured")
+	}
+
+	pages := bot.buildPages(articles)
+	title := fmt.Sprintf("**%s**\n%s", description, utils.CurrentTime())
+	client := utils.BotClient(conf.Timeout)
+	sent := 0
+	for i, embeds := range pages {
+		content := title
+		if len(pages) > 1 {
+			content = fmt.Sprintf("%s (%d/%d)", title, i+1, len(pages))
+		}
+		msg := DiscordMessage{
+			Content:   truncate(content, discordMaxContent),
+			Username:  conf.Username,
+			AvatarURL: conf.AvatarURL,
+			Embeds:    embeds,
+		}
+		if err := bot.post(ctx, client, conf.Webhook, msg); err != nil {
+			return &register.PartialError{Sent: articles[:sent], Err: err}
+		}
+		sent += len(embeds) // 每篇文章一个 embed
+	}
+	fmt.Printf("[*] send to DiscordBot: %d articles in %d messages\n", len(articles), len(pages))
+	return nil
+}
+
+// buildPages 为每篇文章生成 embed，并按数量和总长度限制分页。
+func (bot DiscordBot) buildPages(articles []register.Article) [][]DiscordEmbed {
+	var pages [][]DiscordEmbed
+	var page []DiscordEmbed
+	size := 0
+	for _, article := range articles {
+		embed := DiscordEmbed{
+			Title:       truncate(article.Title, discordMaxTitle),
+			URL:         article.URL,
+			Description: truncate(article.Summary, discordMaxDescription),
+			Color:       discordColor,
+		}
+		if article.Source != "" {
+			embed.Footer = &DiscordFooter{Text: article.Source}
+		}
+		if !article.Published.IsZero() {
+			embed.Timestamp = article.Published.UTC().Format(time.RFC3339)
+		}
+		length := embedLength(embed)
+		if len(page) > 0 && (len(page) >= discordMaxEmbeds || size+length > discordMaxEmbedChars) {
+			pages = append(pages, page)
+			page, size = nil, 0
+		}
+		page = append(page, embed)
+		size += length
+	}
+	if len(page) > 0 {
+		pages = append(pages, page)
+	}
+	return pages
+}
+
+// embedLength 计算 embed 中计入 6000 字符限制的文本长度。
+func embedLength(embed DiscordEmbed) int {
+	length := utf8.RuneCountInString(embed.Title) + utf8.RuneCountInString(embed.Description)
+	if embed.Footer != nil {
+		length += utf8.RuneCountInString(embed.Footer.Text)
+	}
+	return length
+}
+
+// post 发送一条消息，遇到 429 时按 retry_after 等待后重试，ctx 被取消时中断请求和等待。
+func (bot DiscordBot) post(ctx context.Context, client *http.Client, webhook string, msg DiscordMessage) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, "POST", webhook, bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-type", "application/json")
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		respString, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return nil
+		}
+		if resp.StatusCode != http.StatusTooManyRequests {
+			return fmt.Errorf("DiscordBot error: %d %s", resp.StatusCode, respString)
+		}
+
+		wait := retryAfter(resp, respString)
+		if attempt >= discordMaxRetries || wait > discordMaxWait {
+			return fmt.Errorf("DiscordBot rate limited, retry after %s", wait)
+		}
+		fmt.Printf("[*] DiscordBot rate limited, retry after %s\n", wait)
+		if err := utils.Sleep(ctx, wait); err != nil {
+			return fmt.Errorf("DiscordBot rate limited, retry canceled: %s", err.Error())
+		}
+	}
+}
+
+// retryAfter 从 429 响应中读取需要等待的时间，优先使用响应体中的 retry_after。
+func retryAfter(resp *http.Response, body []byte) time.Duration {
+	var limit DiscordRateLimit
+	if err := json.Unmarshal(body, &limit); err == nil && limit.RetryAfter > 0 {
+		return time.Duration(limit.RetryAfter * float64(time.Second))
+	}
+	if seconds, err := strconv.ParseFloat(resp.Header.Get("Retry-After"), 64); err == nil && seconds > 0 {
+		return time.Duration(seconds * float64(time.Second))
+	}
+	return time.Second
+}
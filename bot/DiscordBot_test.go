@@ -0,0 +1,76 @@
+package bot
+
+import (
+	. "SecCrawler/config"
+	"SecCrawler/register"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDiscordPartialFailure(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 第二页失败
+		if atomic.AddInt32(&requests, 1) == 2 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	Cfg = &Config{}
+	Location = time.UTC
+	Cfg.Bot.DiscordBot.Webhook = srv.URL
+
+	var articles []register.Article
+	for i := 0; i < 25; i++ {
+		articles = append(articles, register.Article{URL: fmt.Sprintf("https://a/%d", i), Title: "t", Source: "A"})
+	}
+	if pages := (DiscordBot{}).buildPages(articles); len(pages) != 3 {
+		t.Fatalf("pages = %d, want 3", len(pages))
+	}
+
+	err := DiscordBot{}.Send(articles, "d")
+	var partial *register.PartialError
+	if !errors.As(err, &partial) {
+		t.Fatalf("Send error = %v, want *register.PartialError", err)
+	}
+	if got, want := articleURLs(partial.Sent), articleURLs(articles[:discordMaxEmbeds]); !reflect.DeepEqual(got, want) {
+		t.Errorf("sent = %q, want %q", got, want)
+	}
+	// 失败后不再发送剩余的页
+	if n := atomic.LoadInt32(&requests); n != 2 {
+		t.Errorf("requests = %d, want 2", n)
+	}
+}
+
+func TestDiscordRateLimitCanceled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"message":"rate limited","retry_after":30}`))
+	}))
+	defer srv.Close()
+
+	Cfg = &Config{}
+	Location = time.UTC
+	Cfg.Bot.DiscordBot.Webhook = srv.URL
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := DiscordBot{}.SendContext(ctx, []register.Article{{URL: "https://a/1", Title: "t"}}, "d")
+	if err == nil {
+		t.Fatal("SendContext: want error")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("SendContext returned after %s, want it to stop waiting when ctx is canceled", elapsed)
+	}
+}
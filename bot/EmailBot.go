@@ -0,0 +1,379 @@
+package bot
+
+import (
+	. "SecCrawler/config"
+	"SecCrawler/register"
+	"SecCrawler/utils"
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	htmlTemplate "html/template"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const (
+	emailDefaultSubject = "SecCrawler 安全日报 {{.Date}}（{{.Count}} 篇）"
+	emailMaxSummary     = 200
+)
+
+type EmailBot struct{}
+
+// EmailSubject 邮件主题模板可以使用的字段。
+type EmailSubject struct {
+	Date        string   // 日期，如 2006-01-02
+	Time        string   // 推送时间
+	Description string   // 爬虫描述，多个爬虫以顿号分隔
+	Crawlers    []string // 爬虫名称
+	Count       int      // 文章数量
+}
+
+// emailGroup 邮件正文中一个来源的文章。
+type emailGroup struct {
+	Title    string
+	Articles []register.Article
+}
+
+var emailHTML = htmlTemplate.Must(htmlTemplate.New("email").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"></head>
+<body style="font-family:-apple-system,'Segoe UI','PingFang SC','Microsoft YaHei',sans-serif;color:#24292f;max-width:720px">
+<h2>{{.Subject}}</h2>
+<p style="color:#57606a">{{.Time}}</p>
+{{range .Groups}}<h3 style="border-bottom:1px solid #d0d7de;padding-bottom:4px">{{.Title}}（{{len .Articles}}）</h3>
+<ul>
+{{range .Articles}}<li style="margin-bottom:6px"><a href="{{.URL}}">{{.Title}}</a>{{if .Summary}}<br><span style="color:#57606a;font-size:13px">{{.Summary}}</span>{{end}}</li>
+{{end}}</ul>
+{{end}}<p style="color:#8c959f;font-size:12px">SecCrawler</p>
+</body>
+</html>
+`))
+
+func (bot EmailBot) Config() register.BotConfig {
+	return register.BotConfig{
+		Name:        "EmailBot",
+		Description: "邮件日报",
+	}
+}
+
+// Batch 开启后一次推送中所有爬虫的文章合并为一封邮件。
+func (bot EmailBot) Batch() bool {
+	return Cfg.Bot.EmailBot.Batch
+}
+
+// Send 将一个爬虫的文章作为一封邮件发送。
+func (bot EmailBot) Send(articles []register.Article, description string) error {
+	var crawler string
+	if len(articles) > 0 {
+		crawler = articles[0].Source
+	}
+	return bot.SendBatch([]register.ArticleGroup{{Crawler: crawler, Description: description, Articles: articles}})
+}
+
+// SendBatch 将多个爬虫的文章按来源分组，作为一封邮件发送。
+func (bot EmailBot) SendBatch(groups []register.ArticleGroup) error {
+	conf := Cfg.Bot.EmailBot
+	if conf.Host == "" || conf.From == "" || len(conf.To) == 0 {
+		return errors.New("EmailBot host, from or to is not configured")
+	}
+	from, err := mail.ParseAddress(conf.From)
+	if err != nil {
+		return fmt.Errorf("EmailBot from [%s] error: %s", conf.From, err.Error())
+	}
+	var to []*mail.Address
+	for _, rcpt := range conf.To {
+		addr, err := mail.ParseAddress(rcpt)
+		if err != nil {
+			return fmt.Errorf("EmailBot to [%s] error: %s", rcpt, err.Error())
+		}
+		to = append(to, addr)
+	}
+
+	subject, err := bot.subject(groups)
+	if err != nil {
+		return err
+	}
+	msg, err := bot.buildMessage(from, to, subject, groups)
+	if err != nil {
+		return err
+	}
+	if err := bot.sendMail(from, to, msg); err != nil {
+		return err
+	}
+
+	count := 0
+	for _, group := range groups {
+		count += len(group.Articles)
+	}
+	fmt.Printf("[*] send to EmailBot: %d articles from %d crawlers to %d recipients\n", count, len(groups), len(to))
+	return nil
+}
+
+// subject 按配置的模板生成邮件主题。
+func (bot EmailBot) subject(groups []register.ArticleGroup) (string, error) {
+	text := Cfg.Bot.EmailBot.Subject
+	if text == "" {
+		text = emailDefaultSubject
+	}
+	tmpl, err := template.New("subject").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("EmailBot subject template error: %s", err.Error())
+	}
+
+	data := EmailSubject{
+		Date: time.Now().In(Location).Format("2006-01-02"),
+		Time: utils.CurrentTime(),
+	}
+	var descriptions []string
+	for _, group := range groups {
+		data.Crawlers = append(data.Crawlers, group.Crawler)
+		descriptions = append(descriptions, groupTitle(group))
+		data.Count += len(group.Articles)
+	}
+	data.Description = strings.Join(descriptions, "、")
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("EmailBot subject template error: %s", err.Error())
+	}
+	// 主题中不能包含换行
+	return strings.Join(strings.Fields(b.String()), " "), nil
+}
+
+func groupTitle(group register.ArticleGroup) string {
+	if group.Description != "" {
+		return group.Description
+	}
+	return group.Crawler
+}
+
+// buildMessage 生成包含纯文本和 HTML 两部分的 multipart/alternative 邮件。
+func (bot EmailBot) buildMessage(from *mail.Address, to []*mail.Address, subject string, groups []register.ArticleGroup) ([]byte, error) {
+	var emailGroups []emailGroup
+	var text strings.Builder
+	fmt.Fprintf(&text, "%s\n%s\n", subject, utils.CurrentTime())
+	for _, group := range groups {
+		eg := emailGroup{Title: groupTitle(group)}
+		fmt.Fprintf(&text, "\n%s（%d）\n\n", eg.Title, len(group.Articles))
+		for i, article := range group.Articles {
+			article.Title = strings.Join(strings.Fields(article.Title), " ")
+			article.Summary = truncate(strings.Join(strings.Fields(article.Summary), " "), emailMaxSummary)
+			eg.Articles = append(eg.Articles, article)
+			fmt.Fprintf(&text, "%d. %s\n   %s\n", i+1, article.Title, article.URL)
+		}
+		emailGroups = append(emailGroups, eg)
+	}
+	var html bytes.Buffer
+	err := emailHTML.Execute(&html, map[string]interface{}{
+		"Subject": subject,
+		"Time":    utils.CurrentTime(),
+		"Groups":  emailGroups,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for _, part := range []struct {
+		contentType string
+		content     []byte
+	}{
+		{"text/plain; charset=utf-8", []byte(text.String())},
+		{"text/html; charset=utf-8", html.Bytes()},
+	} {
+		w, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write(part.content); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	var recipients []string
+	for _, addr := range to {
+		recipients = append(recipients, addr.String())
+	}
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", from.String())
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(recipients, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&msg, "Message-ID: %s\r\n", messageID(from.Address))
+	fmt.Fprintf(&msg, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", writer.Boundary())
+	msg.Write(body.Bytes())
+	return msg.Bytes(), nil
+}
+
+func messageID(from string) string {
+	domain := "seccrawler"
+	if at := strings.LastIndex(from, "@"); at >= 0 {
+		domain = from[at+1:]
+	}
+	b := make([]byte, 12)
+	rand.Read(b)
+	return fmt.Sprintf("<%d.%s@%s>", time.Now().UnixNano(), hex.EncodeToString(b), domain)
+}
+
+// sendMail 连接 SMTP 服务器并发送邮件。security 未配置时 465 端口使用 tls，其余端口使用 starttls。
+func (bot EmailBot) sendMail(from *mail.Address, to []*mail.Address, msg []byte) error {
+	conf := Cfg.Bot.EmailBot
+	port := conf.Port
+	security := strings.ToLower(conf.Security)
+	if security == "" {
+		security = "starttls"
+		if port == 465 {
+			security = "tls"
+		}
+	}
+	if port == 0 {
+		port = map[string]int{"tls": 465, "starttls": 587, "none": 25}[security]
+	}
+	timeout := time.Duration(conf.Timeout) * time.Second
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+
+	addr := net.JoinHostPort(conf.Host, strconv.Itoa(port))
+	tlsConfig := &tls.Config{ServerName: conf.Host, MinVersion: tls.VersionTLS12}
+	dialer := &net.Dialer{Timeout: timeout}
+	var conn net.Conn
+	var err error
+	switch security {
+	case "tls":
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	case "starttls", "none":
+		conn, err = dialer.Dial("tcp", addr)
+	default:
+		return fmt.Errorf("EmailBot security [%s] should be starttls, tls or none", conf.Security)
+	}
+	if err != nil {
+		return err
+	}
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	client, err := smtp.NewClient(conn, conf.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+	if security == "starttls" {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return errors.New("EmailBot server does not support STARTTLS")
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	}
+
+	auth, err := bot.auth()
+	if err != nil {
+		return err
+	}
+	if auth != nil {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return errors.New("EmailBot server does not support AUTH")
+		}
+		if err := client.Auth(auth); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(from.Address); err != nil {
+		return err
+	}
+	for _, rcpt := range to {
+		if err := client.Rcpt(rcpt.Address); err != nil {
+			return fmt.Errorf("EmailBot rcpt [%s] error: %s", rcpt.Address, err.Error())
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// auth 返回配置的认证方式，auth 未配置时有用户名则使用 plain。
+func (bot EmailBot) auth() (smtp.Auth, error) {
+	conf := Cfg.Bot.EmailBot
+	mode := strings.ToLower(conf.Auth)
+	if mode == "" {
+		mode = "none"
+		if conf.Username != "" {
+			mode = "plain"
+		}
+	}
+	switch mode {
+	case "plain":
+		return smtp.PlainAuth("", conf.Username, conf.Password, conf.Host), nil
+	case "login":
+		return &loginAuth{username: conf.Username, password: conf.Password, host: conf.Host}, nil
+	case "none":
+		return nil, nil
+	}
+	return nil, fmt.Errorf("EmailBot auth [%s] should be plain, login or none", conf.Auth)
+}
+
+// loginAuth 实现 AUTH LOGIN，与 smtp.PlainAuth 一样只在加密连接或本机上发送密码。
+type loginAuth struct {
+	username, password, host string
+}
+
+func (a *loginAuth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	if !server.TLS && !isLocalhost(server.Name) {
+		return "", nil, errors.New("unencrypted connection")
+	}
+	if server.Name != a.host {
+		return "", nil, errors.New("wrong host name")
+	}
+	return "LOGIN", nil, nil
+}
+
+func (a *loginAuth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if !more {
+		return nil, nil
+	}
+	switch strings.ToLower(strings.TrimSpace(string(fromServer))) {
+	case "username:":
+		return []byte(a.username), nil
+	case "password:":
+		return []byte(a.password), nil
+	}
+	return nil, fmt.Errorf("unexpected server challenge: %s", fromServer)
+}
+
+func isLocalhost(name string) bool {
+	return name == "localhost" || name == "127.0.0.1" || name == "::1"
+}
@@ -5,43 +5,34 @@ import (
 	"SecCrawler/register"
 	"SecCrawler/utils"
 	"fmt"
-	"io/ioutil"
-	"net/http"
-	"strings"
 )
 
 type HexQBot struct{}
 
 func (bot HexQBot) Config() register.BotConfig {
 	return register.BotConfig{
-		Name: "HexQBot",
+		Name:        "HexQBot",
+		Description: "HexQBot QQ群机器人",
 	}
 }
 
 // Send 推送消息给HexQBot。
-func (bot HexQBot) Send(crawlerResult [][]string, description string) error {
+func (bot HexQBot) Send(articles []register.Article, description string) error {
 	var msg string
 
-	for _, i := range crawlerResult {
-		text := fmt.Sprintf("%s\\n%s\\n\\n", i[1], i[0])
+	for _, article := range articles {
+		text := fmt.Sprintf("%s\n%s\n\n", article.Title, article.URL)
 		msg += text
 	}
-	title := fmt.Sprintf("%s\\n%s\\n\\n", description, utils.CurrentTime())
+	title := fmt.Sprintf("%s\n%s\n\n", description, utils.CurrentTime())
 
 	client := utils.BotClient(Cfg.Bot.HexQBot.Timeout)
 
-	data := fmt.Sprintf(`{"msg": "%s", "num": %d, "key": "%s"}`, title+msg, Cfg.Bot.HexQBot.QQGroup, Cfg.Bot.HexQBot.Key)
-	req, err := http.NewRequest("POST", Cfg.Bot.HexQBot.Api, strings.NewReader(data))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-type", "application/json")
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	respString, err := ioutil.ReadAll(resp.Body)
+	respString, err := postJSON(client, Cfg.Bot.HexQBot.Api, map[string]interface{}{
+		"msg": title + msg,
+		"num": Cfg.Bot.HexQBot.QQGroup,
+		"key": Cfg.Bot.HexQBot.Key,
+	})
 	if err != nil {
 		return err
 	}
@@ -31,12 +31,13 @@ type OneBotResponse struct {
 
 func (bot OneBotQQ) Config() register.BotConfig {
 	return register.BotConfig{
-		Name: "OneBotQQ",
+		Name:        "OneBotQQ",
+		Description: "OneBot QQ机器人",
 	}
 }
 
 // Send 推送消息到QQ
-func (bot OneBotQQ) Send(crawlerResult [][]string, description string) error {
+func (bot OneBotQQ) Send(articles []register.Article, description string) error {
 	apiURL := config.Cfg.Bot.OneBotQQ.API
 	groupID := config.Cfg.Bot.OneBotQQ.GroupID
 	userID := config.Cfg.Bot.OneBotQQ.UserID
@@ -48,7 +49,7 @@ func (bot OneBotQQ) Send(crawlerResult [][]string, description string) error {
 	}
 
 	// 构建消息内容
-	message := bot.buildMessage(crawlerResult, description)
+	message := bot.buildMessage(articles, description)
 
 	var err error
 	// 优先发送到群组
@@ -75,22 +76,17 @@ func (bot OneBotQQ) Send(crawlerResult [][]string, description string) error {
 }
 
 // buildMessage 构建消息内容
-func (bot OneBotQQ) buildMessage(crawlerResult [][]string, description string) string {
+func (bot OneBotQQ) buildMessage(articles []register.Article, description string) string {
 	var msgBuilder strings.Builder
 
 	msgBuilder.WriteString(fmt.Sprintf("【%s 安全资讯】\n", description))
 	msgBuilder.WriteString(fmt.Sprintf("时间: %s\n", utils.CurrentTime()))
-	msgBuilder.WriteString(fmt.Sprintf("共 %d 条更新\n", len(crawlerResult)))
+	msgBuilder.WriteString(fmt.Sprintf("共 %d 条更新\n", len(articles)))
 	msgBuilder.WriteString(strings.Repeat("=", 30) + "\n\n")
 
-	for i, result := range crawlerResult {
-		if len(result) >= 2 {
-			title := result[1]
-			url := result[0]
-
-			msgBuilder.WriteString(fmt.Sprintf("%d. %s\n", i+1, title))
-			msgBuilder.WriteString(fmt.Sprintf("🔗 %s\n\n", url))
-		}
+	for i, article := range articles {
+		msgBuilder.WriteString(fmt.Sprintf("%d. %s\n", i+1, article.Title))
+		msgBuilder.WriteString(fmt.Sprintf("🔗 %s\n\n", article.URL))
 
 		// 限制消息长度，避免过长
 		if msgBuilder.Len() > 4000 {
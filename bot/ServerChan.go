@@ -15,16 +15,17 @@ type ServerChan struct{}
 
 func (bot ServerChan) Config() register.BotConfig {
 	return register.BotConfig{
-		Name: "ServerChan",
+		Name:        "ServerChan",
+		Description: "Server酱",
 	}
 }
 
 // Send 推送消息给Server酱。
-func (bot ServerChan) Send(crawlerResult [][]string, description string) error {
+func (bot ServerChan) Send(articles []register.Article, description string) error {
 	var msg string
 
-	for _, i := range crawlerResult {
-		text := fmt.Sprintf("%s\n[%s](%s)\n\n", i[1], i[0], i[0])
+	for _, article := range articles {
+		text := fmt.Sprintf("%s\n[%s](%s)\n\n", article.Title, article.URL, article.URL)
 		msg += text
 	}
 
@@ -0,0 +1,269 @@
+package bot
+
+import (
+	. "SecCrawler/config"
+	"SecCrawler/register"
+	"SecCrawler/utils"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// Slack 消息限制，超出时拆分为多条消息。
+// https://api.slack.com/reference/block-kit/blocks
+const (
+	slackMaxBlocks  = 50    // 单条消息的 block 数量
+	slackMaxSection = 3000  // section 的文本长度
+	slackMaxHeader  = 150   // header 的文本长度
+	slackMaxMessage = 12000 // 单条消息所有 block 文本的总长度，Slack 未公开该限制，取保守值
+	slackMaxTitle   = 300   // 单篇文章标题的长度
+)
+
+const slackPostMessage = "https://slack.com/api/chat.postMessage"
+
+type SlackBot struct{}
+
+// SlackText Block Kit 文本对象。
+type SlackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// SlackBlock Block Kit 中的 header 或 section。
+type SlackBlock struct {
+	Type string     `json:"type"`
+	Text *SlackText `json:"text,omitempty"`
+}
+
+// SlackMessage chat.postMessage 和 incoming webhook 的消息结构。
+type SlackMessage struct {
+	Channel     string       `json:"channel,omitempty"`
+	Text        string       `json:"text"` // 通知和不支持 Block Kit 的客户端中显示的文本
+	Blocks      []SlackBlock `json:"blocks"`
+	UnfurlLinks bool         `json:"unfurl_links"`
+}
+
+// SlackResponse chat.postMessage 的响应。
+type SlackResponse struct {
+	Ok    bool   `json:"ok"`
+	Error string `json:"error"`
+}
+
+func (bot SlackBot) Config() register.BotConfig {
+	return register.BotConfig{
+		Name:        "SlackBot",
+		Description: "Slack机器人",
+	}
+}
+
+// Send 按爬虫对应的频道分组推送文章，每个来源一个 section，超出 Slack 限制时拆分为多条消息。
+func (bot SlackBot) Send(articles []register.Article, description string) error {
+	conf := Cfg.Bot.SlackBot
+	if conf.Token == "" && conf.Webhook == "" {
+		return errors.New("SlackBot webhook or token is not configured")
+	}
+
+	var targets []string
+	grouped := map[string][]register.Article{}
+	for _, article := range articles {
+		target := bot.target(article.Source)
+		if target == "" {
+			return fmt.Errorf("SlackBot channel for [%s] is not configured", article.Source)
+		}
+		if _, ok := grouped[target]; !ok {
+			targets = append(targets, target)
+		}
+		grouped[target] = append(grouped[target], article)
+	}
+
+	// 每个频道的文章互不相同，某个频道失败时在该频道的第一条失败消息处停止，其他频道继续推送，
+	// 只将成功发送的消息中的文章报告为已推送
+	client := utils.BotClient(conf.Timeout)
+	var errs []string
+	var sent []register.Article
+	for _, target := range targets {
+		for _, page := range bot.buildMessages(grouped[target], description) {
+			msg := page.msg
+			var err error
+			if isWebhook(target) {
+				err = bot.postWebhook(client, target, msg)
+			} else {
+				msg.Channel = target
+				err = bot.postMessage(client, msg)
+			}
+			if err != nil {
+				errs = append(errs, err.Error())
+				break
+			}
+			sent = append(sent, page.articles...)
+		}
+	}
+	if len(errs) > 0 {
+		return &register.PartialError{Sent: sent, Err: errors.New(strings.Join(errs, "; "))}
+	}
+	fmt.Printf("[*] send to SlackBot: %d articles to %d channels\n", len(articles), len(targets))
+	return nil
+}
+
+// target 返回爬虫对应的频道或 webhook 地址。配置了 token 时默认发送到 channel，否则发送到 webhook。
+func (bot SlackBot) target(source string) string {
+	conf := Cfg.Bot.SlackBot
+	// viper 会将 map 的键转为小写
+	if target, ok := conf.Channels[strings.ToLower(source)]; ok && target != "" {
+		if isWebhook(target) || conf.Token != "" {
+			return target
+		}
+	}
+	if conf.Token != "" && conf.Channel != "" {
+		return conf.Channel
+	}
+	return conf.Webhook
+}
+
+func isWebhook(target string) bool {
+	return strings.HasPrefix(target, "https://") || strings.HasPrefix(target, "http://")
+}
+
+// slackPage 一条消息及其包含的文章。
+type slackPage struct {
+	msg      SlackMessage
+	articles []register.Article
+}
+
+// buildMessages 生成 Block Kit 消息，每个来源一个 section，文章过多时拆分 section 和消息。
+func (bot SlackBot) buildMessages(articles []register.Article, description string) []slackPage {
+	title := fmt.Sprintf("%s %s", description, utils.CurrentTime())
+	header := SlackBlock{Type: "header", Text: &SlackText{Type: "plain_text", Text: truncate(title, slackMaxHeader)}}
+
+	var sources []string
+	bySource := map[string][]register.Article{}
+	for _, article := range articles {
+		if _, ok := bySource[article.Source]; !ok {
+			sources = append(sources, article.Source)
+		}
+		bySource[article.Source] = append(bySource[article.Source], article)
+	}
+
+	type section struct {
+		text     string
+		articles []register.Article
+	}
+	var sections []section
+	for _, source := range sources {
+		current := section{text: fmt.Sprintf("*%s*", slackEscape(source))}
+		for _, article := range bySource[source] {
+			line := fmt.Sprintf("\n• <%s|%s>", slackURL(article.URL), slackLinkText(article.Title))
+			if utf8.RuneCountInString(current.text)+utf8.RuneCountInString(line) > slackMaxSection {
+				sections = append(sections, current)
+				current = section{text: fmt.Sprintf("*%s*（续）", slackEscape(source))}
+			}
+			current.text += line
+			current.articles = append(current.articles, article)
+		}
+		sections = append(sections, current)
+	}
+
+	var pages []slackPage
+	newPage := func() slackPage {
+		return slackPage{msg: SlackMessage{Text: title, Blocks: []SlackBlock{header}}}
+	}
+	page, size := newPage(), utf8.RuneCountInString(header.Text.Text)
+	for _, s := range sections {
+		length := utf8.RuneCountInString(s.text)
+		if len(page.msg.Blocks) > 1 && (len(page.msg.Blocks) >= slackMaxBlocks || size+length > slackMaxMessage) {
+			pages = append(pages, page)
+			page, size = newPage(), utf8.RuneCountInString(header.Text.Text)
+		}
+		page.msg.Blocks = append(page.msg.Blocks, SlackBlock{Type: "section", Text: &SlackText{Type: "mrkdwn", Text: s.text}})
+		page.articles = append(page.articles, s.articles...)
+		size += length
+	}
+	return append(pages, page)
+}
+
+// postWebhook 通过 incoming webhook 发送消息，成功时响应为 ok。
+func (bot SlackBot) postWebhook(client *http.Client, webhook string, msg SlackMessage) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", webhook, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respString, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("SlackBot webhook error: %d %s", resp.StatusCode, respString)
+	}
+	return nil
+}
+
+// postMessage 通过 chat.postMessage 发送消息到指定频道。
+func (bot SlackBot) postMessage(client *http.Client, msg SlackMessage) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", slackPostMessage, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-type", "application/json; charset=utf-8")
+	req.Header.Set("Authorization", "Bearer "+Cfg.Bot.SlackBot.Token)
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respString, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	var result SlackResponse
+	if err := json.Unmarshal(respString, &result); err != nil {
+		return fmt.Errorf("SlackBot response error: %d %s", resp.StatusCode, respString)
+	}
+	if !result.Ok {
+		return fmt.Errorf("SlackBot [%s] error: %s", msg.Channel, result.Error)
+	}
+	return nil
+}
+
+// slackEscape 转义 mrkdwn 中的控制字符。
+func slackEscape(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
+
+// slackURL 编码链接中会破坏 mrkdwn 链接语法的字符。
+func slackURL(u string) string {
+	return strings.NewReplacer("|", "%7C", "<", "%3C", ">", "%3E", " ", "%20").Replace(u)
+}
+
+// slackLinkText 转义链接文字，| 会截断链接文字，替换为全角字符。
+func slackLinkText(title string) string {
+	title = strings.Join(strings.Fields(title), " ")
+	return slackEscape(strings.ReplaceAll(truncate(title, slackMaxTitle), "|", "｜"))
+}
+
+// truncate 将文本截断为不超过 max 个字符。
+func truncate(text string, max int) string {
+	if utf8.RuneCountInString(text) <= max {
+		return text
+	}
+	runes := []rune(text)
+	return string(runes[:max-1]) + "…"
+}
@@ -0,0 +1,84 @@
+package bot
+
+import (
+	. "SecCrawler/config"
+	"SecCrawler/register"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSlackPartialFailure(t *testing.T) {
+	var mu sync.Mutex
+	requests := map[string]int{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.Path]++
+		n := requests[r.URL.Path]
+		mu.Unlock()
+		// /broken 总是失败，/second 的第二条消息失败
+		if r.URL.Path == "/broken" || (r.URL.Path == "/second" && n == 2) {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	Cfg = &Config{}
+	Location = time.UTC
+	Cfg.Bot.SlackBot.Webhook = srv.URL + "/default"
+	Cfg.Bot.SlackBot.Channels = map[string]string{
+		"broken": srv.URL + "/broken",
+		"paged":  srv.URL + "/second",
+	}
+
+	var articles []register.Article
+	add := func(source string, count int) []register.Article {
+		var list []register.Article
+		for i := 0; i < count; i++ {
+			list = append(list, register.Article{
+				URL:    fmt.Sprintf("https://%s/%d", source, i),
+				Title:  strings.Repeat("t", slackMaxTitle),
+				Source: source,
+			})
+		}
+		articles = append(articles, list...)
+		return list
+	}
+	ok := add("Ok", 2)
+	add("Broken", 2)
+	paged := add("Paged", 60)
+
+	pages := SlackBot{}.buildMessages(paged, "d")
+	if len(pages) < 2 {
+		t.Fatalf("paged articles should be split into several messages, got %d", len(pages))
+	}
+
+	err := SlackBot{}.Send(articles, "d")
+	var partial *register.PartialError
+	if !errors.As(err, &partial) {
+		t.Fatalf("Send error = %v, want *register.PartialError", err)
+	}
+	want := append(append([]register.Article(nil), ok...), pages[0].articles...)
+	if got := articleURLs(partial.Sent); strings.Join(got, " ") != strings.Join(articleURLs(want), " ") {
+		t.Errorf("sent = %q, want %q", got, articleURLs(want))
+	}
+	// 失败后不再发送该频道剩余的消息
+	if requests["/second"] != 2 {
+		t.Errorf("requests to paged webhook = %d, want 2", requests["/second"])
+	}
+}
+
+func articleURLs(articles []register.Article) []string {
+	var urls []string
+	for _, article := range articles {
+		urls = append(urls, article.URL)
+	}
+	return urls
+}
@@ -0,0 +1,220 @@
+package bot
+
+import (
+	. "SecCrawler/config"
+	"SecCrawler/register"
+	"SecCrawler/store"
+	"SecCrawler/utils"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"unicode/utf16"
+)
+
+const (
+	telegramApi        = "https://api.telegram.org"
+	telegramMaxMessage = 4096 // 单条消息的长度，按 UTF-16 计算
+	telegramMaxTitle   = 300
+)
+
+type TelegramBot struct{}
+
+// TelegramMessage sendMessage 的请求参数。
+// https://core.telegram.org/bots/api#sendmessage
+type TelegramMessage struct {
+	ChatID             string                      `json:"chat_id"`
+	Text               string                      `json:"text"`
+	ParseMode          string                      `json:"parse_mode,omitempty"`
+	LinkPreviewOptions *TelegramLinkPreviewOptions `json:"link_preview_options,omitempty"`
+}
+
+type TelegramLinkPreviewOptions struct {
+	IsDisabled bool `json:"is_disabled"`
+}
+
+// TelegramResponse Bot API 的响应。
+type TelegramResponse struct {
+	Ok          bool   `json:"ok"`
+	ErrorCode   int    `json:"error_code"`
+	Description string `json:"description"`
+}
+
+func (bot TelegramBot) Config() register.BotConfig {
+	return register.BotConfig{
+		Name:        "TelegramBot",
+		Description: "Telegram机器人",
+	}
+}
+
+// Send 推送消息到配置的所有 chat，超过 4096 字符时按文章拆分为多条消息。
+// 每个 chat 已收到的文章单独记录，某个 chat 失败时其他 chat 照常推送，重试时只发给未收到的 chat，
+// 只有推送给了所有 chat 的文章才报告为已推送。
+func (bot TelegramBot) Send(articles []register.Article, description string) error {
+	conf := Cfg.Bot.TelegramBot
+	if conf.Token == "" || len(conf.ChatIDs) == 0 {
+		return errors.New("TelegramBot token or ChatIDs is not configured")
+	}
+	parseMode, err := telegramParseMode(conf.ParseMode)
+	if err != nil {
+		return err
+	}
+
+	name := bot.Config().Name
+	client := utils.BotClient(conf.Timeout)
+	var errs []string
+	received := map[string]int{} // 每篇文章已推送给的 chat 数量
+	for _, chatID := range conf.ChatIDs {
+		done, err := store.TargetDelivered(name, chatID, articles)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		var remaining []register.Article
+		for _, article := range articles {
+			if done[article.URL] {
+				received[article.URL]++
+			} else {
+				remaining = append(remaining, article)
+			}
+		}
+		if len(remaining) == 0 {
+			continue
+		}
+		for _, page := range bot.buildTexts(remaining, description, parseMode) {
+			msg := TelegramMessage{ChatID: chatID, Text: page.text, ParseMode: parseMode}
+			if conf.DisablePreview {
+				msg.LinkPreviewOptions = &TelegramLinkPreviewOptions{IsDisabled: true}
+			}
+			if err := bot.sendMessage(client, msg); err != nil {
+				errs = append(errs, err.Error())
+				break
+			}
+			if err := store.MarkTargetDelivered(name, chatID, page.articles); err != nil {
+				errs = append(errs, err.Error())
+				break
+			}
+			for _, article := range page.articles {
+				received[article.URL]++
+			}
+		}
+	}
+	if len(errs) > 0 {
+		var sent []register.Article
+		for _, article := range articles {
+			if received[article.URL] == len(conf.ChatIDs) {
+				sent = append(sent, article)
+			}
+		}
+		return &register.PartialError{Sent: sent, Err: errors.New(strings.Join(errs, "; "))}
+	}
+	fmt.Printf("[*] send to TelegramBot: %d articles to %d chats\n", len(articles), len(conf.ChatIDs))
+	return nil
+}
+
+// telegramParseMode 校验消息格式，默认为 MarkdownV2。
+func telegramParseMode(mode string) (string, error) {
+	switch strings.ToLower(mode) {
+	case "", "markdownv2":
+		return "MarkdownV2", nil
+	case "html":
+		return "HTML", nil
+	}
+	return "", fmt.Errorf("TelegramBot ParseMode [%s] should be MarkdownV2 or HTML", mode)
+}
+
+// telegramPage 一条消息及其包含的文章。
+type telegramPage struct {
+	text     string
+	articles []register.Article
+}
+
+// buildTexts 生成消息文本，每条消息以标题开头，超出长度限制时拆分。
+func (bot TelegramBot) buildTexts(articles []register.Article, description, parseMode string) []telegramPage {
+	var header string
+	if parseMode == "HTML" {
+		header = fmt.Sprintf("<b>%s</b>\n%s\n", html.EscapeString(description), html.EscapeString(utils.CurrentTime()))
+	} else {
+		header = fmt.Sprintf("*%s*\n%s\n", escapeMarkdownV2(description), escapeMarkdownV2(utils.CurrentTime()))
+	}
+
+	var pages []telegramPage
+	page := telegramPage{text: header}
+	for i, article := range articles {
+		title := truncate(strings.Join(strings.Fields(article.Title), " "), telegramMaxTitle)
+		var line string
+		if parseMode == "HTML" {
+			line = fmt.Sprintf("\n%d. <a href=\"%s\">%s</a>", i+1, html.EscapeString(article.URL), html.EscapeString(title))
+		} else {
+			line = fmt.Sprintf("\n%d\\. [%s](%s)", i+1, escapeMarkdownV2(title), escapeMarkdownV2URL(article.URL))
+		}
+		if len(page.articles) > 0 && utf16Len(page.text)+utf16Len(line) > telegramMaxMessage {
+			pages = append(pages, page)
+			page = telegramPage{text: header}
+		}
+		page.text += line
+		page.articles = append(page.articles, article)
+	}
+	return append(pages, page)
+}
+
+// sendMessage 调用 sendMessage 发送一条消息。
+func (bot TelegramBot) sendMessage(client *http.Client, msg TelegramMessage) error {
+	api := strings.TrimRight(Cfg.Bot.TelegramBot.Api, "/")
+	if api == "" {
+		api = telegramApi
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", api+"/bot"+Cfg.Bot.TelegramBot.Token+"/sendMessage", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		// 错误信息中的 URL 包含 token
+		return fmt.Errorf("TelegramBot [%s] request error: %s", msg.ChatID, strings.ReplaceAll(err.Error(), Cfg.Bot.TelegramBot.Token, "***"))
+	}
+	defer resp.Body.Close()
+	respString, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	var result TelegramResponse
+	if err := json.Unmarshal(respString, &result); err != nil {
+		return fmt.Errorf("TelegramBot response error: %d %s", resp.StatusCode, respString)
+	}
+	if !result.Ok {
+		return fmt.Errorf("TelegramBot [%s] error: %d %s", msg.ChatID, result.ErrorCode, result.Description)
+	}
+	return nil
+}
+
+// escapeMarkdownV2 转义 MarkdownV2 中的所有特殊字符。
+// https://core.telegram.org/bots/api#markdownv2-style
+func escapeMarkdownV2(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		if strings.ContainsRune("\\_*[]()~`>#+-=|{}.!", r) {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// escapeMarkdownV2URL 转义内联链接 URL 部分中的 ) 和 \。
+func escapeMarkdownV2URL(u string) string {
+	return strings.NewReplacer("\\", "\\\\", ")", "\\)").Replace(u)
+}
+
+func utf16Len(text string) int {
+	return len(utf16.Encode([]rune(text)))
+}
@@ -0,0 +1,142 @@
+package bot
+
+import (
+	. "SecCrawler/config"
+	"SecCrawler/register"
+	"SecCrawler/store"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestEscapeMarkdownV2(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"plain", "Log4j 漏洞分析", "Log4j 漏洞分析"},
+		{"underscore and star", "snake_case *bold*", `snake\_case \*bold\*`},
+		{"brackets", "[PoC] exploit(1)", `\[PoC\] exploit\(1\)`},
+		{"all special", "_*[]()~`>#+-=|{}.!", "\\_\\*\\[\\]\\(\\)\\~\\`\\>\\#\\+\\-\\=\\|\\{\\}\\.\\!"},
+		{"backslash", `C:\Windows`, `C:\\Windows`},
+		{"cve and date", "CVE-2021-44228 2024.01.10", `CVE\-2021\-44228 2024\.01\.10`},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := escapeMarkdownV2(tt.text); got != tt.want {
+				t.Errorf("escapeMarkdownV2(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEscapeMarkdownV2URL(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{"plain", "https://example.com/a_b*c[1].html?x=1&y=-2#top", "https://example.com/a_b*c[1].html?x=1&y=-2#top"},
+		{"parenthesis", "https://en.wikipedia.org/wiki/Log4Shell_(vulnerability)", `https://en.wikipedia.org/wiki/Log4Shell_(vulnerability\)`},
+		{"several parentheses", "https://a/)()", `https://a/\)(\)`},
+		{"backslash", `https://a/\)`, `https://a/\\\)`},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := escapeMarkdownV2URL(tt.url); got != tt.want {
+				t.Errorf("escapeMarkdownV2URL(%q) = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTelegramPartialFailure(t *testing.T) {
+	var mu sync.Mutex
+	messages := map[string]int{} // 每个 chat 收到的消息数量
+	failB := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg TelegramMessage
+		json.NewDecoder(r.Body).Decode(&msg)
+		mu.Lock()
+		defer mu.Unlock()
+		// chat b 的第二条消息失败
+		if msg.ChatID == "b" && failB && messages["b"] == 1 {
+			w.Write([]byte(`{"ok":false,"error_code":400,"description":"Bad Request"}`))
+			return
+		}
+		messages[msg.ChatID]++
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	Cfg = &Config{}
+	Location = time.UTC
+	Cfg.Store.Path = filepath.Join(t.TempDir(), "test.db")
+	Cfg.Bot.TelegramBot.Token = "token"
+	Cfg.Bot.TelegramBot.ChatIDs = []string{"a", "b"}
+	Cfg.Bot.TelegramBot.Api = srv.URL
+	store.StoreInit()
+	defer store.Close()
+
+	var articles []register.Article
+	for i := 0; i < 40; i++ {
+		articles = append(articles, register.Article{
+			URL:    fmt.Sprintf("https://a/%d", i),
+			Title:  strings.Repeat("t", telegramMaxTitle),
+			Source: "A",
+		})
+	}
+	if _, err := store.SaveArticles("A", articles, []string{"TelegramBot"}); err != nil {
+		t.Fatalf("save articles: %v", err)
+	}
+	pages := TelegramBot{}.buildTexts(articles, "d", "MarkdownV2")
+	if len(pages) < 3 {
+		t.Fatalf("pages = %d, want at least 3", len(pages))
+	}
+
+	err := TelegramBot{}.Send(articles, "d")
+	var partial *register.PartialError
+	if !errors.As(err, &partial) {
+		t.Fatalf("Send error = %v, want *register.PartialError", err)
+	}
+	// 只有两个 chat 都收到的第一页报告为已推送
+	if got, want := articleURLs(partial.Sent), articleURLs(pages[0].articles); !reflect.DeepEqual(got, want) {
+		t.Errorf("sent = %q, want %q", got, want)
+	}
+	if messages["a"] != len(pages) || messages["b"] != 1 {
+		t.Fatalf("messages = %v, want a: %d, b: 1", messages, len(pages))
+	}
+
+	// 与调度器相同，只标记已推送的文章，其余文章重试时只发给 chat b
+	if err := store.MarkDelivered("TelegramBot", "A", partial.Sent); err != nil {
+		t.Fatalf("mark delivered: %v", err)
+	}
+	pending, err := store.Pending("TelegramBot", "A")
+	if err != nil {
+		t.Fatalf("pending: %v", err)
+	}
+	if len(pending) != len(articles)-len(partial.Sent) {
+		t.Fatalf("pending = %d, want %d", len(pending), len(articles)-len(partial.Sent))
+	}
+	mu.Lock()
+	failB = false
+	mu.Unlock()
+	if err := (TelegramBot{}).Send(pending, "d"); err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	retry := TelegramBot{}.buildTexts(pending, "d", "MarkdownV2")
+	if messages["a"] != len(pages) || messages["b"] != 1+len(retry) {
+		t.Errorf("messages after retry = %v, want a: %d, b: %d", messages, len(pages), 1+len(retry))
+	}
+}
@@ -0,0 +1,122 @@
+package bot
+
+import (
+	. "SecCrawler/config"
+	"SecCrawler/register"
+	"SecCrawler/utils"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// WebhookBot 通用 webhook 推送，按配置的模板生成请求体，无需为新的推送服务编写代码。
+type WebhookBot struct {
+	conf WebhookStruct
+}
+
+// WebhookData 请求体模板可以使用的字段。
+type WebhookData struct {
+	Description string             // 爬虫描述
+	Time        string             // 推送时间
+	Date        string             // 日期，如 2006-01-02
+	Count       int                // 文章数量
+	Articles    []register.Article // 文章列表
+	Text        string             // 描述、时间和所有文章标题与链接组成的纯文本
+}
+
+// webhookFuncs 模板中可以使用的函数：
+// json 将值编码为 JSON（字符串会带上引号），jsonEscape 转义字符串以放入 JSON 字符串的引号中，
+// join 以分隔符连接字符串列表，add 整数相加（用于序号）。
+var webhookFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	"jsonEscape": func(s string) (string, error) {
+		b, err := json.Marshal(s)
+		if err != nil {
+			return "", err
+		}
+		return string(b[1 : len(b)-1]), nil
+	},
+	"join": strings.Join,
+	"add": func(a, b int) int {
+		return a + b
+	},
+}
+
+func NewWebhookBot(conf WebhookStruct) *WebhookBot {
+	return &WebhookBot{conf: conf}
+}
+
+func (bot WebhookBot) Config() register.BotConfig {
+	return register.BotConfig{
+		Name:        bot.conf.Name,
+		Description: bot.conf.Description,
+	}
+}
+
+// Template 解析请求体模板。
+func (bot WebhookBot) Template() (*template.Template, error) {
+	return template.New(bot.conf.Name).Funcs(webhookFuncs).Parse(bot.conf.Body)
+}
+
+// Send 按模板生成请求体并发送到 webhook，响应状态码不是 2xx 时视为失败。
+func (bot WebhookBot) Send(articles []register.Article, description string) error {
+	tmpl, err := bot.Template()
+	if err != nil {
+		return fmt.Errorf("webhook [%s] body template error: %s", bot.conf.Name, err.Error())
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "%s\n%s\n\n", description, utils.CurrentTime())
+	for _, article := range articles {
+		fmt.Fprintf(&text, "%s\n%s\n\n", article.Title, article.URL)
+	}
+	data := WebhookData{
+		Description: description,
+		Time:        utils.CurrentTime(),
+		Date:        time.Now().In(Location).Format("2006-01-02"),
+		Count:       len(articles),
+		Articles:    articles,
+		Text:        text.String(),
+	}
+	var body bytes.Buffer
+	if err := tmpl.Execute(&body, data); err != nil {
+		return fmt.Errorf("webhook [%s] body template error: %s", bot.conf.Name, err.Error())
+	}
+
+	method := strings.ToUpper(bot.conf.Method)
+	if method == "" {
+		method = "POST"
+	}
+	req, err := http.NewRequest(method, bot.conf.URL, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-type", "application/json")
+	for key, value := range bot.conf.Headers {
+		req.Header.Set(key, value)
+	}
+
+	client := utils.BotClient(bot.conf.Timeout)
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respString, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook [%s] error: %d %s", bot.conf.Name, resp.StatusCode, respString)
+	}
+	fmt.Printf("[*] send to %s: %s\n", bot.conf.Name, respString)
+	return nil
+}
@@ -15,16 +15,17 @@ type WgpSecBot struct{}
 
 func (bot WgpSecBot) Config() register.BotConfig {
 	return register.BotConfig{
-		Name: "WgpSecBot",
+		Name:        "WgpSecBot",
+		Description: "WgpSecBot 推送",
 	}
 }
 
 // Send 推送消息给Server酱。
-func (bot WgpSecBot) Send(crawlerResult [][]string, description string) error {
+func (bot WgpSecBot) Send(articles []register.Article, description string) error {
 	var msg string
 
-	for _, i := range crawlerResult {
-		text := fmt.Sprintf("%s\n%s\n\n", i[1], i[0])
+	for _, article := range articles {
+		text := fmt.Sprintf("%s\n%s\n\n", article.Title, article.URL)
 		msg += text
 	}
 	title := fmt.Sprintf("%s\n%s\n\n", description, utils.CurrentTime())
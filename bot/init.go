@@ -3,28 +3,49 @@ package bot
 import (
 	. "SecCrawler/config"
 	"SecCrawler/register"
+	"log"
 )
 
 func BotInit() {
-	if Cfg.Bot.DingBot.Enabled {
-		register.RegisterBot(&DingBot{})
-	}
-	if Cfg.Bot.FeishuBot.Enabled {
-		register.RegisterBot(&FeishuBot{})
-	}
-	if Cfg.Bot.HexQBot.Enabled {
-		register.RegisterBot(&HexQBot{})
-	}
-	if Cfg.Bot.ServerChan.Enabled {
-		register.RegisterBot(&ServerChan{})
-	}
-	if Cfg.Bot.WecomBot.Enabled {
-		register.RegisterBot(&WecomBot{})
-	}
-	if Cfg.Bot.WgpSecBot.Enabled {
-		register.RegisterBot(&WgpSecBot{})
+	registerBot(Cfg.Bot.DingBot.Enabled, &DingBot{})
+	registerBot(Cfg.Bot.FeishuBot.Enabled, &FeishuBot{})
+	registerBot(Cfg.Bot.HexQBot.Enabled, &HexQBot{})
+	registerBot(Cfg.Bot.ServerChan.Enabled, &ServerChan{})
+	registerBot(Cfg.Bot.WecomBot.Enabled, &WecomBot{})
+	registerBot(Cfg.Bot.WgpSecBot.Enabled, &WgpSecBot{})
+	registerBot(Cfg.Bot.OneBotQQ.Enabled, &OneBotQQ{})
+	registerBot(Cfg.Bot.SlackBot.Enabled, &SlackBot{})
+	registerBot(Cfg.Bot.DiscordBot.Enabled, &DiscordBot{})
+	registerBot(Cfg.Bot.TelegramBot.Enabled, &TelegramBot{})
+	registerBot(Cfg.Bot.EmailBot.Enabled, &EmailBot{})
+
+	for _, conf := range Cfg.Bot.Webhooks {
+		bot := NewWebhookBot(conf)
+		if !conf.Enabled {
+			register.RegisterDisabledBot(bot.Config())
+			continue
+		}
+		if conf.Name == "" || conf.URL == "" {
+			log.Printf("webhook [%s] is missing name or url, skipped\n", conf.Name)
+			continue
+		}
+		if _, ok := register.GetBotMap()[conf.Name]; ok {
+			log.Printf("webhook [%s] conflicts with an existing bot, skipped\n", conf.Name)
+			continue
+		}
+		if _, err := bot.Template(); err != nil {
+			log.Printf("webhook [%s] body template error: %s, skipped\n", conf.Name, err.Error())
+			continue
+		}
+		register.RegisterBot(bot)
 	}
-	if Cfg.Bot.OneBotQQ.Enabled {
-		register.RegisterBot(&OneBotQQ{})
+}
+
+// registerBot 注册启用的Bot，未启用的Bot只记录配置，用于在API中展示。
+func registerBot(enabled bool, bot register.Bot) {
+	if enabled {
+		register.RegisterBot(bot)
+		return
 	}
+	register.RegisterDisabledBot(bot.Config())
 }
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"time"
+	_ "time/tzdata" // Windows等系统可能缺少时区数据库
 
 	"github.com/spf13/viper"
 	"gopkg.in/yaml.v2"
@@ -21,12 +23,16 @@ const Banner = `
 // 全局Config
 var Cfg *Config
 
+// 定时任务与时间展示使用的时区
+var Location *time.Location
+
 var (
 	Test       bool
 	Version    bool
 	Help       bool
 	Generate   bool
 	ConfigFile string
+	HashKey    string
 
 	GITHUB    string = "https://github.com/Le0nsec/SecCrawler"
 	TAG       string = "v2.2"
@@ -41,9 +47,29 @@ func DefaultConfig() Config {
 			CrawlerProxyEnabled: false,
 			BotProxyEnabled:     false,
 		},
+		Fetcher: FetcherStruct{
+			Timeout:     15 * time.Second,
+			Retries:     3,
+			BackoffMin:  time.Second,
+			BackoffMax:  30 * time.Second,
+			MaxBodySize: 10 << 20,
+			Concurrency: 2,
+			Conditional: true,
+			Cache: FetcherCacheStruct{
+				Enabled: false,
+				Dir:     "cache",
+				TTL:     10 * time.Minute,
+			},
+		},
+		Store: StoreStruct{
+			Path: "SecCrawler.db",
+		},
 		Cron: CronStruct{
-			Enabled: false,
-			Time:    11,
+			Enabled:  false,
+			Timezone: "Asia/Shanghai",
+			Schedules: []ScheduleStruct{
+				{Name: "daily", Spec: "0 11 * * *"},
+			},
 		},
 		Api: ApiStruct{
 			Enabled: false,
@@ -51,8 +77,18 @@ func DefaultConfig() Config {
 			Host:    "127.0.0.1",
 			Port:    8080,
 			Auth:    "auth_key_here",
+			TLS: ApiTLSStruct{
+				Enabled:    false,
+				Cert:       "cert.pem",
+				Key:        "key.pem",
+				SelfSigned: true,
+			},
+			RateLimit: ApiRateLimitStruct{PerIP: 600},
 		},
 		Crawler: CrawlerStruct{
+			MaxLookback: 24 * time.Hour,
+			Workers:     4,
+			Timeout:     10 * time.Minute,
 			EdgeForum:   EdgeForumStruct{Enabled: false},
 			XianZhi:     XianZhiStruct{Enabled: false, UseChromeDriver: true, CustomRSSURL: ""},
 			SeebugPaper: SeebugPaperStruct{Enabled: false},
@@ -75,6 +111,44 @@ func DefaultConfig() Config {
 			HuoxianZone: HuoxianZoneStruct{
 				Enabled: true,
 			},
+			Feeds: []FeedStruct{
+				{
+					Enabled:     false,
+					Name:        "Example",
+					Description: "自定义 RSS/Atom 订阅源示例",
+					URL:         "https://example.com/feed.xml",
+					Limit:       20,
+				},
+			},
+			Pages: []PageStruct{
+				{
+					Enabled:     false,
+					Name:        "ExamplePage",
+					Description: "自定义 HTML 列表页示例",
+					URL:         "https://example.com/blog/",
+					Item:        "article",
+					Title:       "h2 a",
+					Link:        "h2 a",
+					Date:        "time",
+					DateAttr:    "datetime",
+					Layout:      "2006-01-02",
+					Limit:       20,
+				},
+			},
+			JsonApis: []JsonApiStruct{
+				{
+					Enabled:      false,
+					Name:         "ExampleApi",
+					Description:  "自定义 JSON 接口示例（Discourse 最新主题）",
+					URL:          "https://forum.example.com/latest.json",
+					Items:        "topic_list.topics",
+					Title:        "title",
+					LinkTemplate: "https://forum.example.com/t/{slug}/{id}",
+					Date:         "created_at",
+					Pagination:   JsonPaginationStruct{Type: "page", Start: 0},
+					Limit:        20,
+				},
+			},
 		},
 		Bot: BotStruct{
 			WecomBot: WecomBotStruct{
@@ -109,6 +183,50 @@ func DefaultConfig() Config {
 				Key:     "xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx",
 				Timeout: 2,
 			},
+			SlackBot: SlackBotStruct{
+				Enabled: false,
+				Webhook: "https://hooks.slack.com/services/xxxxxxxxx/xxxxxxxxxxx/xxxxxxxxxxxxxxxxxxxxxxxx",
+				Channel: "#security",
+				Timeout: 5,
+			},
+			DiscordBot: DiscordBotStruct{
+				Enabled: false,
+				Webhook: "https://discord.com/api/webhooks/xxxxxxxxxxxxxxxxxx/xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx",
+				Timeout: 5,
+			},
+			TelegramBot: TelegramBotStruct{
+				Enabled:   false,
+				Token:     "000000000:xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx",
+				ChatIDs:   []string{"@channel"},
+				ParseMode: "MarkdownV2",
+				Timeout:   5,
+			},
+			EmailBot: EmailBotStruct{
+				Enabled:  false,
+				Host:     "smtp.example.com",
+				Port:     587,
+				Security: "starttls",
+				Auth:     "plain",
+				Username: "seccrawler@example.com",
+				Password: "xxxxxxxxxxxxxxxx",
+				From:     "SecCrawler <seccrawler@example.com>",
+				To:       []string{"security@example.com"},
+				Subject:  "SecCrawler 安全日报 {{.Date}}（{{.Count}} 篇）",
+				Batch:    true,
+				Timeout:  30,
+			},
+			Webhooks: []WebhookStruct{
+				{
+					Enabled:     false,
+					Name:        "ExampleWebhook",
+					Description: "通用 webhook 示例",
+					URL:         "https://example.com/webhook",
+					Method:      "POST",
+					Headers:     map[string]string{"Content-Type": "application/json"},
+					Body:        `{"text": {{json .Text}}}`,
+					Timeout:     5,
+				},
+			},
 		},
 	}
 }
@@ -161,6 +279,14 @@ func ConfigInit() {
 		Cfg.Bot.OneBotQQ.UserID = viper.GetInt64("Bot.OneBotQQ.user_id")
 		Cfg.Bot.OneBotQQ.AccessToken = viper.GetString("Bot.OneBotQQ.access_token")
 
+		if Cfg.Cron.Timezone == "" {
+			Cfg.Cron.Timezone = "Asia/Shanghai"
+		}
+		Location, err = time.LoadLocation(Cfg.Cron.Timezone)
+		if err != nil {
+			log.Fatalf("load timezone [%s] error: %s\n", Cfg.Cron.Timezone, err.Error())
+		}
+
 		fmt.Printf("[*] load config success!\n\n")
 	}
 }
@@ -1,9 +1,13 @@
 package config
 
+import "time"
+
 type Config struct {
 	ChromeDriver string `yaml:"ChromeDriver"`
 
 	Proxy   ProxyStruct   `yaml:"Proxy"`
+	Fetcher FetcherStruct `yaml:"Fetcher"`
+	Store   StoreStruct   `yaml:"Store"`
 	Cron    CronStruct    `yaml:"Cron"`
 	Api     ApiStruct     `yaml:"Api"`
 	Crawler CrawlerStruct `yaml:"Crawler"`
@@ -11,8 +15,17 @@ type Config struct {
 }
 
 type CronStruct struct {
-	Enabled bool  `yaml:"enabled"`
-	Time    uint8 `yaml:"time"`
+	Enabled   bool             `yaml:"enabled"`
+	Time      uint8            `yaml:"time,omitempty"` // 旧版配置，未配置 schedules 时每天该整点推送
+	Timezone  string           `yaml:"timezone"`
+	Schedules []ScheduleStruct `yaml:"schedules"`
+}
+
+type ScheduleStruct struct {
+	Name     string   `yaml:"name"`
+	Spec     string   `yaml:"spec"`
+	Crawlers []string `yaml:"crawlers"`
+	Bots     []string `yaml:"bots"`
 }
 
 type ProxyStruct struct {
@@ -21,15 +34,85 @@ type ProxyStruct struct {
 	BotProxyEnabled     bool   `yaml:"BotProxyEnabled"`
 }
 
+// FetcherStruct 爬虫共用的 HTTP 请求设置，未配置的项使用默认值。
+type FetcherStruct struct {
+	Timeout     time.Duration                `yaml:"timeout"`     // 单次请求超时，默认 15s
+	Retries     int                          `yaml:"retries"`     // 网络错误、429 和 5xx 时的最大重试次数，默认 3，-1 表示不重试
+	BackoffMin  time.Duration                `yaml:"BackoffMin"`  // 首次重试的等待时间，之后按指数增长，默认 1s
+	BackoffMax  time.Duration                `yaml:"BackoffMax"`  // 重试等待时间上限，默认 30s
+	MaxBodySize int64                        `yaml:"MaxBodySize"` // 响应体大小上限（字节），默认 10MB
+	Concurrency int                          `yaml:"concurrency"` // 同一主机的最大并发请求数，默认 2
+	Interval    time.Duration                `yaml:"interval"`    // 同一主机两次请求的最小间隔，默认不限制
+	Hosts       []FetcherHostStruct          `yaml:"hosts,omitempty"`
+	Profiles    map[string]map[string]string `yaml:"profiles,omitempty"` // 自定义请求头模板，与内置模板 browser、api 同名时覆盖内置模板
+	Conditional bool                         `yaml:"conditional"`        // RSS/Atom 订阅源记录 ETag/Last-Modified 并发送条件请求，304 视为没有新文章
+	Cache       FetcherCacheStruct           `yaml:"cache"`
+}
+
+// FetcherCacheStruct 本地响应缓存，有效期内的重复请求直接使用缓存，便于反复测试。
+type FetcherCacheStruct struct {
+	Enabled bool          `yaml:"enabled"`
+	Dir     string        `yaml:"dir"` // 缓存目录，默认为 cache
+	TTL     time.Duration `yaml:"ttl"` // 缓存有效期，默认 10m
+}
+
+// FetcherHostStruct 单个主机的并发与频率限制，覆盖全局设置。
+type FetcherHostStruct struct {
+	Host        string        `yaml:"host"`
+	Concurrency int           `yaml:"concurrency,omitempty"`
+	Interval    time.Duration `yaml:"interval,omitempty"`
+}
+
+type StoreStruct struct {
+	Path string `yaml:"path"`
+}
+
 type ApiStruct struct {
-	Enabled bool   `yaml:"enabled"`
-	Debug   bool   `yaml:"debug"`
-	Host    string `yaml:"host"`
-	Port    uint16 `yaml:"port"`
-	Auth    string `yaml:"auth"`
+	Enabled bool           `yaml:"enabled"`
+	Debug   bool           `yaml:"debug"`
+	Host    string         `yaml:"host"`
+	Port    uint16         `yaml:"port"`
+	Auth    string         `yaml:"auth"` // 拥有全部权限的密钥，明文保存，建议改用 keys
+	Keys    []ApiKeyStruct `yaml:"keys"`
+	TLS     ApiTLSStruct   `yaml:"tls"`
+
+	CorsOrigins    []string           `yaml:"CorsOrigins"`    // 允许跨域请求的来源，如 https://example.com，留空表示全部
+	TrustedProxies []string           `yaml:"TrustedProxies"` // 反向代理的 IP 或 CIDR，只信任来自这些地址的 X-Forwarded-For、X-Forwarded-Proto 和 X-Forwarded-Host
+	Allow          []string           `yaml:"allow"`          // 允许访问的 IP 或 CIDR，留空表示全部
+	Deny           []string           `yaml:"deny"`           // 禁止访问的 IP 或 CIDR，优先于 allow
+	RateLimit      ApiRateLimitStruct `yaml:"RateLimit"`
+}
+
+// ApiTLSStruct API 的 HTTPS 配置。
+type ApiTLSStruct struct {
+	Enabled    bool     `yaml:"enabled"`
+	Cert       string   `yaml:"cert"`            // 证书文件
+	Key        string   `yaml:"key"`             // 私钥文件
+	SelfSigned bool     `yaml:"SelfSigned"`      // 证书文件不存在时生成自签名证书
+	Hosts      []string `yaml:"hosts,omitempty"` // 自签名证书包含的域名或 IP，默认为 host、localhost 和 127.0.0.1
+}
+
+// ApiRateLimitStruct API 的请求频率限制，超出时返回 429。
+type ApiRateLimitStruct struct {
+	PerIP  int `yaml:"PerIP"`  // 每个 IP 每分钟的请求数，0 表示不限制
+	PerKey int `yaml:"PerKey"` // 每个 API Key 每分钟的请求数，0 表示不限制
+	Burst  int `yaml:"burst"`  // 允许连续发出的请求数，默认与每分钟的请求数相同
+}
+
+// ApiKeyStruct 具名的 API Key，配置中只保存哈希，使用 -hash 参数生成。
+type ApiKeyStruct struct {
+	Name    string   `yaml:"name"`
+	Hash    string   `yaml:"hash"`              // sha256:<十六进制>
+	Scopes  []string `yaml:"scopes"`            // read 读取文章与状态、trigger 触发抓取和推送、admin 全部权限
+	Expires string   `yaml:"expires,omitempty"` // 过期时间，RFC3339 或 2006-01-02，留空表示不过期
+	Sources []string `yaml:"sources,omitempty"` // 允许访问的爬虫，留空表示全部
 }
 
 type CrawlerStruct struct {
+	MaxLookback time.Duration `yaml:"MaxLookback"`
+	Workers     int           `yaml:"workers"`
+	Timeout     time.Duration `yaml:"timeout"`
+
 	EdgeForum   EdgeForumStruct   `yaml:"EdgeForum"`
 	XianZhi     XianZhiStruct     `yaml:"XianZhi"`
 	SeebugPaper SeebugPaperStruct `yaml:"SeebugPaper"`
@@ -40,16 +123,24 @@ type CrawlerStruct struct {
 	Lab         LabStruct         `yaml:"Lab"`
 	HuoxianZone HuoxianZoneStruct `yaml:"HuoxianZone"`
 	SocialMedia SocialMediaStruct `yaml:"SocialMedia"`
+	Feeds       []FeedStruct      `yaml:"Feeds"`
+	Pages       []PageStruct      `yaml:"Pages"`
+	JsonApis    []JsonApiStruct   `yaml:"JsonApis"`
 }
 
 type BotStruct struct {
-	WecomBot   WecomBotStruct   `yaml:"WecomBot"`
-	FeishuBot  FeishuBotStruct  `yaml:"FeishuBot"`
-	DingBot    DingBotStruct    `yaml:"DingBot"`
-	HexQBot    HexQBotStruct    `yaml:"HexQBot"`
-	ServerChan ServerChanStruct `yaml:"ServerChan"`
-	WgpSecBot  WgpSecBotStruct  `yaml:"WgpSecBot"`
-	OneBotQQ   OneBotQQStruct   `yaml:"OneBotQQ"`
+	WecomBot    WecomBotStruct    `yaml:"WecomBot"`
+	FeishuBot   FeishuBotStruct   `yaml:"FeishuBot"`
+	DingBot     DingBotStruct     `yaml:"DingBot"`
+	HexQBot     HexQBotStruct     `yaml:"HexQBot"`
+	ServerChan  ServerChanStruct  `yaml:"ServerChan"`
+	WgpSecBot   WgpSecBotStruct   `yaml:"WgpSecBot"`
+	OneBotQQ    OneBotQQStruct    `yaml:"OneBotQQ"`
+	SlackBot    SlackBotStruct    `yaml:"SlackBot"`
+	DiscordBot  DiscordBotStruct  `yaml:"DiscordBot"`
+	TelegramBot TelegramBotStruct `yaml:"TelegramBot"`
+	EmailBot    EmailBotStruct    `yaml:"EmailBot"`
+	Webhooks    []WebhookStruct   `yaml:"Webhooks"`
 }
 
 type WecomBotStruct struct {
@@ -90,47 +181,199 @@ type WgpSecBotStruct struct {
 	Timeout uint8  `yaml:"timeout"`
 }
 
+// SlackBotStruct Slack 机器人，配置 token 时使用 chat.postMessage，否则使用 incoming webhook。
+type SlackBotStruct struct {
+	Enabled  bool              `yaml:"enabled"`
+	Webhook  string            `yaml:"webhook"`
+	Token    string            `yaml:"token"`
+	Channel  string            `yaml:"channel"`
+	Channels map[string]string `yaml:"channels,omitempty"` // 爬虫名称到频道的映射，值也可以是 incoming webhook 地址
+	Timeout  uint8             `yaml:"timeout"`
+}
+
+// DiscordBotStruct Discord webhook 机器人。
+type DiscordBotStruct struct {
+	Enabled   bool   `yaml:"enabled"`
+	Webhook   string `yaml:"webhook"`
+	Username  string `yaml:"username,omitempty"`  // 覆盖 webhook 的默认名称
+	AvatarURL string `yaml:"AvatarURL,omitempty"` // 覆盖 webhook 的默认头像
+	Timeout   uint8  `yaml:"timeout"`
+}
+
+// TelegramBotStruct Telegram 机器人，通过 sendMessage 推送到私聊、群组或频道。
+type TelegramBotStruct struct {
+	Enabled        bool     `yaml:"enabled"`
+	Token          string   `yaml:"token"`
+	ChatIDs        []string `yaml:"ChatIDs"`        // chat id 或频道用户名（如 @channel）
+	ParseMode      string   `yaml:"ParseMode"`      // MarkdownV2 或 HTML
+	DisablePreview bool     `yaml:"DisablePreview"` // 关闭链接预览
+	Api            string   `yaml:"api,omitempty"`  // Bot API 地址，默认为 https://api.telegram.org
+	Timeout        uint8    `yaml:"timeout"`
+}
+
+// EmailBotStruct 通过 SMTP 发送邮件日报。
+type EmailBotStruct struct {
+	Enabled  bool     `yaml:"enabled"`
+	Host     string   `yaml:"host"`
+	Port     int      `yaml:"port"`
+	Security string   `yaml:"security"` // starttls、tls 或 none
+	Auth     string   `yaml:"auth"`     // plain、login 或 none
+	Username string   `yaml:"username"`
+	Password string   `yaml:"password"`
+	From     string   `yaml:"from"`
+	To       []string `yaml:"to"`
+	Subject  string   `yaml:"subject"` // 邮件主题模板，使用 text/template 语法
+	Batch    bool     `yaml:"batch"`   // 将一次推送中所有爬虫的文章合并为一封邮件
+	Timeout  uint8    `yaml:"timeout"`
+}
+
+// WebhookStruct 通用 webhook 推送，请求体使用 text/template 模板生成。
+type WebhookStruct struct {
+	Enabled     bool              `yaml:"enabled"`
+	Name        string            `yaml:"name"`
+	Description string            `yaml:"description"`
+	URL         string            `yaml:"url"`
+	Method      string            `yaml:"method,omitempty"` // 默认为 POST
+	Headers     map[string]string `yaml:"headers,omitempty"`
+	Body        string            `yaml:"body"`
+	Timeout     uint8             `yaml:"timeout"`
+}
+
+// CrawlScheduleStruct 爬虫独立的抓取计划，未配置时随推送计划抓取。
+type CrawlScheduleStruct struct {
+	Interval time.Duration `yaml:"interval,omitempty"`
+	Cron     string        `yaml:"cron,omitempty"`
+}
+
+// FeedStruct 通用 RSS/Atom 订阅源。
+type FeedStruct struct {
+	Enabled             bool              `yaml:"enabled"`
+	Name                string            `yaml:"name"`
+	Description         string            `yaml:"description"`
+	URL                 string            `yaml:"url"`
+	Layout              string            `yaml:"layout,omitempty"`   // 发布时间格式，留空时自动解析
+	Timezone            string            `yaml:"timezone,omitempty"` // 按 layout 解析时使用的时区，默认同 Cron.timezone
+	Profile             string            `yaml:"profile,omitempty"`  // 请求头模板，默认为 browser
+	Headers             map[string]string `yaml:"headers,omitempty"`
+	Limit               int               `yaml:"limit,omitempty"`   // 单次最多抓取的文章数，0 表示不限制
+	UseGUID             bool              `yaml:"UseGUID,omitempty"` // 使用 guid 而不是 link 作为文章链接
+	CrawlScheduleStruct `yaml:",inline" mapstructure:",squash"`
+}
+
+// PageStruct 通用 HTML 列表页，使用 CSS 选择器提取文章。
+// title、link、date 的选择器均相对于 item 匹配到的元素，留空时使用 item 元素本身。
+type PageStruct struct {
+	Enabled             bool              `yaml:"enabled"`
+	Name                string            `yaml:"name"`
+	Description         string            `yaml:"description"`
+	URL                 string            `yaml:"url"`
+	BaseURL             string            `yaml:"BaseURL,omitempty"` // 补全相对链接，默认为 url
+	Item                string            `yaml:"item"`              // 每篇文章所在元素的选择器
+	Title               string            `yaml:"title,omitempty"`
+	Link                string            `yaml:"link,omitempty"`
+	LinkAttr            string            `yaml:"LinkAttr,omitempty"` // 链接所在属性，默认为 href
+	Date                string            `yaml:"date,omitempty"`     // 留空表示列表不提供发布时间，遇到上次抓取的最新文章即停止，首次运行只抓取前 10 篇
+	DateAttr            string            `yaml:"DateAttr,omitempty"` // 发布时间所在属性，留空时取元素文本
+	Layout              string            `yaml:"layout,omitempty"`   // 发布时间格式，默认为 RFC3339
+	Timezone            string            `yaml:"timezone,omitempty"` // 解析发布时间使用的时区，默认同 Cron.timezone
+	Profile             string            `yaml:"profile,omitempty"`  // 请求头模板，默认为 browser
+	Headers             map[string]string `yaml:"headers,omitempty"`
+	Limit               int               `yaml:"limit,omitempty"` // 单次最多抓取的文章数，0 表示不限制
+	CrawlScheduleStruct `yaml:",inline" mapstructure:",squash"`
+}
+
+// JsonApiStruct 通用 JSON 接口，使用 JSONPath 风格的路径提取文章，如 data[*].attributes.title。
+// 除 items 外的路径均相对于单篇文章，开头的 $ 可省略。
+type JsonApiStruct struct {
+	Enabled             bool                 `yaml:"enabled"`
+	Name                string               `yaml:"name"`
+	Description         string               `yaml:"description"`
+	URL                 string               `yaml:"url"`
+	Items               string               `yaml:"items,omitempty"` // 文章列表所在路径，留空表示响应本身就是列表
+	Title               string               `yaml:"title"`
+	Link                string               `yaml:"link,omitempty"`
+	LinkTemplate        string               `yaml:"LinkTemplate,omitempty"` // 链接模板，{path} 会替换为文章中对应路径的值，优先于 link
+	Date                string               `yaml:"date,omitempty"`         // 留空表示接口不提供发布时间，遇到上次抓取的最新文章即停止，首次运行只抓取第一页的前 10 篇
+	Layout              string               `yaml:"layout,omitempty"`       // 发布时间格式，默认为 RFC3339，unix 和 unixms 表示秒和毫秒时间戳
+	Timezone            string               `yaml:"timezone,omitempty"`
+	Author              string               `yaml:"author,omitempty"`
+	Summary             string               `yaml:"summary,omitempty"`
+	Tags                string               `yaml:"tags,omitempty"`
+	Include             []JsonFilterStruct   `yaml:"include,omitempty"` // 需同时满足所有条件
+	Exclude             []JsonFilterStruct   `yaml:"exclude,omitempty"` // 满足任意条件即跳过
+	Pagination          JsonPaginationStruct `yaml:"pagination,omitempty"`
+	Profile             string               `yaml:"profile,omitempty"` // 请求头模板，默认为 api
+	Headers             map[string]string    `yaml:"headers,omitempty"`
+	Limit               int                  `yaml:"limit,omitempty"` // 单次最多抓取的文章数，0 表示不限制
+	CrawlScheduleStruct `yaml:",inline" mapstructure:",squash"`
+}
+
+// JsonFilterStruct 文章过滤条件，字段有多个值时任意一个满足即可。
+type JsonFilterStruct struct {
+	Field   string   `yaml:"field"`
+	Values  []string `yaml:"values,omitempty"`  // 等于其中任意一个值
+	Pattern string   `yaml:"pattern,omitempty"` // 匹配正则表达式
+}
+
+// JsonPaginationStruct 翻页方式，遇到水位线之前的文章后不再翻页。
+type JsonPaginationStruct struct {
+	Type     string `yaml:"type,omitempty"`     // offset、page 或 cursor，留空表示不翻页
+	Param    string `yaml:"param,omitempty"`    // 分页的查询参数名，默认与 type 相同
+	Start    int    `yaml:"start,omitempty"`    // 起始的 offset 或页码
+	Step     int    `yaml:"step,omitempty"`     // offset 每页的增量，默认为本页的文章数
+	Next     string `yaml:"next,omitempty"`     // 下一页游标所在路径，值为完整 URL 时直接请求该 URL
+	MaxPages int    `yaml:"MaxPages,omitempty"` // 单次最多翻页数，默认为 5
+}
+
 type EdgeForumStruct struct {
-	Enabled bool `yaml:"enabled"`
+	Enabled             bool `yaml:"enabled"`
+	CrawlScheduleStruct `yaml:",inline" mapstructure:",squash"`
 }
 
 type XianZhiStruct struct {
-	Enabled         bool   `yaml:"enabled"`
-	UseChromeDriver bool   `yaml:"UseChromeDriver"`
-	CustomRSSURL    string `yaml:"CustomRSSURL"`
+	Enabled             bool   `yaml:"enabled"`
+	UseChromeDriver     bool   `yaml:"UseChromeDriver"`
+	CustomRSSURL        string `yaml:"CustomRSSURL"`
+	CrawlScheduleStruct `yaml:",inline" mapstructure:",squash"`
 }
 
 type SeebugPaperStruct struct {
-	Enabled bool `yaml:"enabled"`
+	Enabled             bool `yaml:"enabled"`
+	CrawlScheduleStruct `yaml:",inline" mapstructure:",squash"`
 }
 
 type AnquankeStruct struct {
-	Enabled bool `yaml:"enabled"`
+	Enabled             bool `yaml:"enabled"`
+	CrawlScheduleStruct `yaml:",inline" mapstructure:",squash"`
 }
 
 type TttangStruct struct {
-	Enabled bool `yaml:"enabled"`
+	Enabled             bool `yaml:"enabled"`
+	CrawlScheduleStruct `yaml:",inline" mapstructure:",squash"`
 }
 
 type QiAnXinStruct struct {
-	Enabled bool `yaml:"enabled"`
+	Enabled             bool `yaml:"enabled"`
+	CrawlScheduleStruct `yaml:",inline" mapstructure:",squash"`
 }
 
 type DongJianStruct struct {
-	Enabled bool `yaml:"enabled"`
+	Enabled             bool `yaml:"enabled"`
+	CrawlScheduleStruct `yaml:",inline" mapstructure:",squash"`
 }
 
 type LabStruct struct {
-	Enabled     bool              `yaml:"enabled"`
-	NoahLab     NoahLabStruct     `yaml:"NoahLab"`
-	Blog360     Blog360Struct     `yaml:"Blog360"`
-	Nsfocus     NsfocusStruct     `yaml:"Nsfocus"`
-	Xlab        XlabStruct        `yaml:"Xlab"`
-	AlphaLab    AlphaLabStruct    `yaml:"AlphaLab"`
-	Netlab      NetlabStruct      `yaml:"Netlab"`
-	RiskivyBlog RiskivyBlogStruct `yaml:"RiskivyBlog"`
-	TSRCBlog    TSRCBlogStruct    `yaml:"TSRCBlog"`
-	X1cT34m     X1cT34mStruct     `yaml:"X1cT34m"`
+	Enabled             bool              `yaml:"enabled"`
+	NoahLab             NoahLabStruct     `yaml:"NoahLab"`
+	Blog360             Blog360Struct     `yaml:"Blog360"`
+	Nsfocus             NsfocusStruct     `yaml:"Nsfocus"`
+	Xlab                XlabStruct        `yaml:"Xlab"`
+	AlphaLab            AlphaLabStruct    `yaml:"AlphaLab"`
+	Netlab              NetlabStruct      `yaml:"Netlab"`
+	RiskivyBlog         RiskivyBlogStruct `yaml:"RiskivyBlog"`
+	TSRCBlog            TSRCBlogStruct    `yaml:"TSRCBlog"`
+	X1cT34m             X1cT34mStruct     `yaml:"X1cT34m"`
+	CrawlScheduleStruct `yaml:",inline" mapstructure:",squash"`
 }
 
 type SocialMediaStruct struct {
@@ -139,16 +382,18 @@ type SocialMediaStruct struct {
 }
 
 type Xstruct struct {
-	Enabled      bool     `yaml:"enabled"`
-	Key          string   `yaml:"key"`
-	Secret       string   `yaml:"secret"`
-	AccessToken  string   `yaml:"accessToken"`
-	AccessSecret string   `yaml:"accessSecret"`
-	IDs          []string `yaml:"IDs"`
+	Enabled             bool     `yaml:"enabled"`
+	Key                 string   `yaml:"key"`
+	Secret              string   `yaml:"secret"`
+	AccessToken         string   `yaml:"accessToken"`
+	AccessSecret        string   `yaml:"accessSecret"`
+	IDs                 []string `yaml:"IDs"`
+	CrawlScheduleStruct `yaml:",inline" mapstructure:",squash"`
 }
 
 type HuoxianZoneStruct struct {
-	Enabled bool `yaml:"enabled"`
+	Enabled             bool `yaml:"enabled"`
+	CrawlScheduleStruct `yaml:",inline" mapstructure:",squash"`
 }
 
 type NoahLabStruct struct {
@@ -3,11 +3,13 @@ package crawler
 import (
 	"SecCrawler/config"
 	. "SecCrawler/config"
+	"SecCrawler/fetcher"
 	"SecCrawler/register"
 	"SecCrawler/utils"
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
-	"net/http"
 	"regexp"
 	"strings"
 	"time"
@@ -23,15 +25,17 @@ func (crawler XianZhi) Config() register.CrawlerConfig {
 	return register.CrawlerConfig{
 		Name:        "XianZhi",
 		Description: "先知安全技术社区",
+		Interval:    Cfg.Crawler.XianZhi.Interval,
+		Cron:        Cfg.Crawler.XianZhi.Cron,
 	}
 }
 
-// Get 获取先知安全技术社区前24小时内文章。
-func (crawler XianZhi) Get() ([][]string, error) {
-	var resultSlice [][]string
+// Get 获取先知安全技术社区水位线之后的新文章。
+func (crawler XianZhi) Get(ctx context.Context, cursor register.Cursor) ([]register.Article, error) {
+	var resultSlice []register.Article
 
 	if config.Cfg.Crawler.XianZhi.UseChromeDriver {
-		text, err := fetchXianZhiBySelenium()
+		text, err := fetchXianZhiBySelenium(ctx)
 		if err != nil {
 			return nil, err
 		}
@@ -45,7 +49,7 @@ func (crawler XianZhi) Get() ([][]string, error) {
 			if err != nil {
 				return nil, err
 			}
-			if !utils.IsIn24Hours(t) {
+			if !cursor.IsNew(t) {
 				// 默认时间顺序是从近到远
 				break
 			}
@@ -54,31 +58,24 @@ func (crawler XianZhi) Get() ([][]string, error) {
 			fmt.Println(match[1:][2])
 			fmt.Printf("%s\n\n", match[1:][1])
 
-			resultSlice = append(resultSlice, match[1:][1:])
+			resultSlice = append(resultSlice, register.Article{
+				URL:       match[1:][1],
+				Title:     match[1:][2],
+				Published: t,
+				Source:    crawler.Config().Name,
+			})
 		}
 	} else {
 		if config.Cfg.Crawler.XianZhi.CustomRSSURL == "" {
 			return nil, errors.New("ChromeDriver is disabled and no custom RSS URL is specified")
 		}
-		client := utils.CrawlerClient()
-
-		req, err := http.NewRequest("GET", config.Cfg.Crawler.XianZhi.CustomRSSURL, nil)
+		resp, err := fetcher.Get(ctx, fetcher.Request{URL: config.Cfg.Crawler.XianZhi.CustomRSSURL, Crawler: "XianZhi"})
 		if err != nil {
 			return nil, err
 		}
 
-		req.Header.Set("Cache-Control", "no-cache")
-		req.Header.Set("Upgrade-Insecure-Requests", "1")
-		req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/96.0.4664.55 Safari/537.36")
-		req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/webp,image/apng,*/*;q=0.8,application/signed-exchange;v=b3;q=0.9")
-		resp, err := client.Do(req)
-		if err != nil {
-			return nil, err
-		}
-		defer resp.Body.Close()
-
 		fp := gofeed.NewParser()
-		feed, err := fp.Parse(resp.Body)
+		feed, err := fp.Parse(bytes.NewReader(resp.Body))
 		if err != nil {
 			return nil, err
 		}
@@ -90,7 +87,7 @@ func (crawler XianZhi) Get() ([][]string, error) {
 			if err != nil {
 				return nil, err
 			}
-			if !utils.IsIn24Hours(t) {
+			if !cursor.IsNew(t) {
 				break
 			}
 
@@ -98,20 +95,25 @@ func (crawler XianZhi) Get() ([][]string, error) {
 			fmt.Println(item.Title)
 			fmt.Printf("%s\n\n", item.Link)
 
-			var s []string
-			s = append(s, item.Link, item.Title)
-			resultSlice = append(resultSlice, s)
+			resultSlice = append(resultSlice, register.Article{
+				URL:       item.Link,
+				Title:     item.Title,
+				Published: t,
+				Source:    crawler.Config().Name,
+				Summary:   item.Description,
+				Tags:      item.Categories,
+			})
 		}
 	}
 
 	if len(resultSlice) == 0 {
-		return nil, errors.New("no records in the last 24 hours")
+		return nil, register.ErrNoRecords
 	}
 	return resultSlice, nil
 }
 
 // fetchXianZhiBySelenium 使用selenium爬取先知社区，因为先知有cookie动态反爬。
-func fetchXianZhiBySelenium() (string, error) {
+func fetchXianZhiBySelenium(ctx context.Context) (string, error) {
 	opts := []selenium.ServiceOption{}
 	caps := selenium.Capabilities{
 		"browserName": "chrome",
@@ -169,6 +171,8 @@ func fetchXianZhiBySelenium() (string, error) {
 		return "", err
 	}
 
-	time.Sleep(3 * time.Second)
+	if err := utils.Sleep(ctx, 3*time.Second); err != nil {
+		return "", err
+	}
 	return text, nil
 }
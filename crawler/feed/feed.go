@@ -0,0 +1,127 @@
+package feed
+
+import (
+	. "SecCrawler/config"
+	"SecCrawler/fetcher"
+	"SecCrawler/register"
+	"SecCrawler/utils"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// Feed 通用的 RSS/Atom 爬虫，订阅源由 FeedStruct 描述。
+type Feed struct {
+	conf FeedStruct
+}
+
+func New(conf FeedStruct) *Feed {
+	return &Feed{conf: conf}
+}
+
+func (crawler Feed) Config() register.CrawlerConfig {
+	return register.CrawlerConfig{
+		Name:        crawler.conf.Name,
+		Description: crawler.conf.Description,
+		Interval:    crawler.conf.Interval,
+		Cron:        crawler.conf.Cron,
+	}
+}
+
+// Get 获取订阅源水位线之后的新文章。
+func (crawler Feed) Get(ctx context.Context, cursor register.Cursor) ([]register.Article, error) {
+	resp, err := fetcher.Get(ctx, fetcher.Request{
+		URL:     crawler.conf.URL,
+		Profile: crawler.conf.Profile,
+		Headers: crawler.conf.Headers,
+		Crawler: crawler.conf.Name,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fp := gofeed.NewParser()
+	feed, err := fp.Parse(bytes.NewReader(resp.Body))
+	if err != nil {
+		return nil, err
+	}
+
+	var resultSlice []register.Article
+	fmt.Printf("[*] [%s] crawler result:\n%s\n\n", crawler.conf.Name, utils.CurrentTime())
+
+	for _, item := range feed.Items {
+		t, err := crawler.published(item)
+		if err != nil {
+			log.Printf("[%s] skip item [%s]: %s\n", crawler.conf.Name, item.Title, err.Error())
+			continue
+		}
+		// 部分订阅源存在置顶或乱序的条目，因此不在遇到旧文章时提前结束
+		if !cursor.IsNew(t) {
+			continue
+		}
+
+		link := item.Link
+		if crawler.conf.UseGUID || link == "" {
+			link = item.GUID
+		}
+		var author string
+		if item.Author != nil {
+			author = item.Author.Name
+		}
+
+		fmt.Println(t.In(Location).Format("2006/01/02 15:04:05"))
+		fmt.Println(item.Title)
+		fmt.Printf("%s\n\n", link)
+
+		resultSlice = append(resultSlice, register.Article{
+			URL:       link,
+			Title:     item.Title,
+			Published: t,
+			Author:    author,
+			Source:    crawler.conf.Name,
+			Summary:   item.Description,
+			Tags:      item.Categories,
+		})
+		if crawler.conf.Limit > 0 && len(resultSlice) >= crawler.conf.Limit {
+			break
+		}
+	}
+
+	if len(resultSlice) == 0 {
+		return nil, register.ErrNoRecords
+	}
+	return resultSlice, nil
+}
+
+// published 解析条目的发布时间。配置了 layout 时按其解析原始时间字符串，
+// 否则使用 gofeed 解析好的发布时间，缺失时退回到更新时间。
+func (crawler Feed) published(item *gofeed.Item) (time.Time, error) {
+	if crawler.conf.Layout == "" {
+		if item.PublishedParsed != nil {
+			return *item.PublishedParsed, nil
+		}
+		if item.UpdatedParsed != nil {
+			return *item.UpdatedParsed, nil
+		}
+		return time.Time{}, errors.New("no published or updated time")
+	}
+
+	loc := Location
+	if crawler.conf.Timezone != "" {
+		var err error
+		loc, err = time.LoadLocation(crawler.conf.Timezone)
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
+	raw := item.Published
+	if raw == "" {
+		raw = item.Updated
+	}
+	return time.ParseInLocation(crawler.conf.Layout, raw, loc)
+}
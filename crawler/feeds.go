@@ -0,0 +1,43 @@
+package crawler
+
+import (
+	. "SecCrawler/config"
+)
+
+// builtinFeeds 内置的 RSS/Atom 订阅源，沿用各自原有的配置项开关。
+func builtinFeeds() []FeedStruct {
+	return []FeedStruct{
+		{
+			Enabled:             Cfg.Crawler.SeebugPaper.Enabled,
+			Name:                "SeebugPaper",
+			Description:         "SeebugPaper-安全技术精粹",
+			URL:                 "https://paper.seebug.org/rss/",
+			CrawlScheduleStruct: Cfg.Crawler.SeebugPaper.CrawlScheduleStruct,
+		},
+		{
+			Enabled:             Cfg.Crawler.Tttang.Enabled,
+			Name:                "Tttang",
+			Description:         "跳跳糖-安全与分享社区",
+			URL:                 "http://tttang.com/rss.xml",
+			CrawlScheduleStruct: Cfg.Crawler.Tttang.CrawlScheduleStruct,
+		},
+		{
+			Enabled:             Cfg.Crawler.QiAnXin.Enabled,
+			Name:                "QiAnXin",
+			Description:         "奇安信攻防社区",
+			URL:                 "https://forum.butian.net/Rss",
+			Layout:              "2006-01-02 15:04:05",
+			Timezone:            "Asia/Shanghai",
+			UseGUID:             true,
+			CrawlScheduleStruct: Cfg.Crawler.QiAnXin.CrawlScheduleStruct,
+		},
+		// 暂时删除洞见微信聚合，优化推送体验，有需要可在 Feeds 中自行添加
+		// {
+		// 	Enabled:     Cfg.Crawler.DongJian.Enabled,
+		// 	Name:        "DongJian",
+		// 	Description: "洞见微信聚合",
+		// 	URL:         "http://wechat.doonsec.com/bayes_rss.xml",
+		// 	Limit:       10,
+		// },
+	}
+}
@@ -2,43 +2,81 @@ package crawler
 
 import (
 	. "SecCrawler/config"
+	"SecCrawler/crawler/feed"
+	"SecCrawler/crawler/jsonapi"
 	"SecCrawler/crawler/lab"
+	"SecCrawler/crawler/page"
 	"SecCrawler/crawler/socialmedia"
 	"SecCrawler/register"
+	"log"
 )
 
 func CrawlerInit() {
-	if Cfg.Crawler.Anquanke.Enabled {
-		register.RegisterCrawler(&Anquanke{})
+	registerCrawler(Cfg.Crawler.XianZhi.Enabled, &XianZhi{})
+	registerCrawler(Cfg.Crawler.Lab.Enabled, &lab.Lab{})
+	for _, conf := range builtinPages() {
+		registerCrawler(conf.Enabled, page.New(conf))
 	}
-	if Cfg.Crawler.EdgeForum.Enabled {
-		register.RegisterCrawler(&EdgeForum{})
+	for _, conf := range builtinJsonApis() {
+		registerCrawler(conf.Enabled, jsonapi.New(conf))
 	}
-	if Cfg.Crawler.QiAnXin.Enabled {
-		register.RegisterCrawler(&QiAnXin{})
+	for _, conf := range builtinFeeds() {
+		registerCrawler(conf.Enabled, feed.New(conf))
 	}
-	if Cfg.Crawler.SeebugPaper.Enabled {
-		register.RegisterCrawler(&SeebugPaper{})
-	}
-	if Cfg.Crawler.Tttang.Enabled {
-		register.RegisterCrawler(&Tttang{})
-	}
-	if Cfg.Crawler.XianZhi.Enabled {
-		register.RegisterCrawler(&XianZhi{})
-	}
-	// 暂时删除洞见微信聚合，优化推送体验
-	// if Cfg.Crawler.DongJian.Enabled {
-	// 	register.RegisterCrawler(&DongJian{})
-	// }
-	if Cfg.Crawler.Lab.Enabled {
-		register.RegisterCrawler(&lab.Lab{})
+	registerCrawler(Cfg.Crawler.SocialMedia.Enabled && Cfg.Crawler.SocialMedia.X.Enabled, &socialmedia.X{})
+
+	for _, conf := range Cfg.Crawler.Feeds {
+		if !conf.Enabled {
+			register.RegisterDisabledCrawler(feed.New(conf).Config())
+			continue
+		}
+		if conf.Name == "" || conf.URL == "" {
+			log.Printf("feed [%s] is missing name or url, skipped\n", conf.Name)
+			continue
+		}
+		if _, ok := register.GetCrawler(conf.Name); ok {
+			log.Printf("feed [%s] conflicts with an existing crawler, skipped\n", conf.Name)
+			continue
+		}
+		register.RegisterCrawler(feed.New(conf))
 	}
-	if Cfg.Crawler.HuoxianZone.Enabled {
-		register.RegisterCrawler(&HuoxianZone{})
+	for _, conf := range Cfg.Crawler.Pages {
+		if !conf.Enabled {
+			register.RegisterDisabledCrawler(page.New(conf).Config())
+			continue
+		}
+		if conf.Name == "" || conf.URL == "" || conf.Item == "" {
+			log.Printf("page [%s] is missing name, url or item, skipped\n", conf.Name)
+			continue
+		}
+		if _, ok := register.GetCrawler(conf.Name); ok {
+			log.Printf("page [%s] conflicts with an existing crawler, skipped\n", conf.Name)
+			continue
+		}
+		register.RegisterCrawler(page.New(conf))
 	}
-	if Cfg.Crawler.SocialMedia.Enabled {
-		if Cfg.Crawler.SocialMedia.X.Enabled {
-			register.RegisterCrawler(&socialmedia.X{})
+	for _, conf := range Cfg.Crawler.JsonApis {
+		if !conf.Enabled {
+			register.RegisterDisabledCrawler(jsonapi.New(conf).Config())
+			continue
+		}
+		if conf.Name == "" || conf.URL == "" || conf.Title == "" {
+			log.Printf("json api [%s] is missing name, url or title, skipped\n", conf.Name)
+			continue
+		}
+		if _, ok := register.GetCrawler(conf.Name); ok {
+			log.Printf("json api [%s] conflicts with an existing crawler, skipped\n", conf.Name)
+			continue
 		}
+		register.RegisterCrawler(jsonapi.New(conf))
+	}
+}
+
+// registerCrawler 注册启用的爬虫，未启用的爬虫只记录配置，用于在API中展示。
+func registerCrawler(enabled bool, crawler register.Crawler) {
+	if enabled {
+		register.RegisterCrawler(crawler)
+		return
 	}
+	register.RegisterDisabledCrawler(crawler.Config())
 }
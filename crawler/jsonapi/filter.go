@@ -0,0 +1,63 @@
+package jsonapi
+
+import (
+	. "SecCrawler/config"
+	"fmt"
+	"regexp"
+)
+
+type filter struct {
+	field   string
+	values  map[string]bool
+	pattern *regexp.Regexp
+}
+
+func compileFilters(confs []JsonFilterStruct) ([]filter, error) {
+	var filters []filter
+	for _, conf := range confs {
+		f := filter{field: conf.Field, values: map[string]bool{}}
+		for _, value := range conf.Values {
+			f.values[value] = true
+		}
+		if conf.Pattern != "" {
+			pattern, err := regexp.Compile(conf.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("filter [%s]: %s", conf.Field, err.Error())
+			}
+			f.pattern = pattern
+		}
+		filters = append(filters, f)
+	}
+	return filters, nil
+}
+
+// match 判断文章字段的任意一个值是否满足条件。
+func (f filter) match(item interface{}) bool {
+	for _, value := range lookupStrings(item, f.field) {
+		if f.values[value] {
+			return true
+		}
+		if f.pattern != nil && f.pattern.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchAll(item interface{}, filters []filter) bool {
+	for _, f := range filters {
+		if !f.match(item) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchAny(item interface{}, filters []filter) bool {
+	for _, f := range filters {
+		if f.match(item) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,275 @@
+package jsonapi
+
+import (
+	. "SecCrawler/config"
+	"SecCrawler/fetcher"
+	"SecCrawler/register"
+	"SecCrawler/utils"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var placeholder = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// JsonApi 通用的 JSON 接口爬虫，接口结构由 JsonApiStruct 描述。
+type JsonApi struct {
+	conf JsonApiStruct
+}
+
+func New(conf JsonApiStruct) *JsonApi {
+	return &JsonApi{conf: conf}
+}
+
+func (crawler JsonApi) Config() register.CrawlerConfig {
+	return register.CrawlerConfig{
+		Name:        crawler.conf.Name,
+		Description: crawler.conf.Description,
+		Interval:    crawler.conf.Interval,
+		Cron:        crawler.conf.Cron,
+	}
+}
+
+// Get 逐页获取接口水位线之后的新文章。
+func (crawler JsonApi) Get(ctx context.Context, cursor register.Cursor) ([]register.Article, error) {
+	include, err := compileFilters(crawler.conf.Include)
+	if err != nil {
+		return nil, err
+	}
+	exclude, err := compileFilters(crawler.conf.Exclude)
+	if err != nil {
+		return nil, err
+	}
+	pagination := crawler.conf.Pagination
+	maxPages := pagination.MaxPages
+	if maxPages <= 0 {
+		maxPages = 5
+	}
+	if pagination.Type == "" {
+		maxPages = 1
+	}
+	limit := crawler.conf.Limit
+	if crawler.conf.Date == "" && cursor.ID == "" {
+		// 首次运行没有上次抓取的文章可以作为停止位置，只抓取第一页中最新的几篇
+		maxPages = 1
+		if limit <= 0 || limit > register.FirstRunLimit {
+			limit = register.FirstRunLimit
+		}
+	}
+
+	var resultSlice []register.Article
+	fmt.Printf("[*] [%s] crawler result:\n%s\n\n", crawler.conf.Name, utils.CurrentTime())
+
+	position, next := pagination.Start, ""
+	for page := 0; page < maxPages; page++ {
+		pageURL, err := crawler.pageURL(position, next, page)
+		if err != nil {
+			return nil, err
+		}
+		doc, err := crawler.fetch(ctx, pageURL)
+		if err != nil {
+			return nil, err
+		}
+
+		items := lookup(doc, crawler.conf.Items)
+		if len(items) == 1 {
+			if list, ok := items[0].([]interface{}); ok {
+				items = list
+			}
+		}
+		if len(items) == 0 {
+			break
+		}
+
+		done := false
+		for _, item := range items {
+			if !matchAll(item, include) || matchAny(item, exclude) {
+				continue
+			}
+			article, err := crawler.article(item)
+			if err != nil {
+				log.Printf("[%s] skip item [%s]: %s\n", crawler.conf.Name, article.Title, err.Error())
+				continue
+			}
+			if crawler.conf.Date == "" {
+				// 接口不提供发布时间时按从新到旧排列处理，遇到上次抓取的最新文章即停止
+				if article.URL == cursor.ID {
+					done = true
+					break
+				}
+			} else if !cursor.IsNew(article.Published) {
+				// 本页可能存在置顶的旧文章，因此处理完本页后再停止翻页
+				done = true
+				continue
+			}
+
+			if !article.Published.IsZero() {
+				fmt.Println(article.Published.In(Location).Format("2006/01/02 15:04:05"))
+			}
+			fmt.Println(article.Title)
+			fmt.Printf("%s\n\n", article.URL)
+
+			resultSlice = append(resultSlice, article)
+			if limit > 0 && len(resultSlice) >= limit {
+				return resultSlice, nil
+			}
+		}
+		if done {
+			break
+		}
+
+		switch pagination.Type {
+		case "offset":
+			if pagination.Step > 0 {
+				position += pagination.Step
+			} else {
+				position += len(items)
+			}
+		case "page":
+			position++
+		case "cursor":
+			next = lookupString(doc, pagination.Next)
+		}
+		if pagination.Type == "cursor" && next == "" {
+			break
+		}
+	}
+
+	if len(resultSlice) == 0 {
+		return nil, register.ErrNoRecords
+	}
+	return resultSlice, nil
+}
+
+// pageURL 生成第 page 页的请求地址，cursor 分页时 next 为上一页返回的游标。
+func (crawler JsonApi) pageURL(position int, next string, page int) (string, error) {
+	pagination := crawler.conf.Pagination
+	if pagination.Type == "cursor" && strings.HasPrefix(next, "http") {
+		return next, nil
+	}
+
+	u, err := url.Parse(crawler.conf.URL)
+	if err != nil {
+		return "", err
+	}
+	param := pagination.Param
+	if param == "" {
+		param = pagination.Type
+	}
+	query := u.Query()
+	switch pagination.Type {
+	case "":
+		return crawler.conf.URL, nil
+	case "offset", "page":
+		query.Set(param, strconv.Itoa(position))
+	case "cursor":
+		if page == 0 {
+			return crawler.conf.URL, nil
+		}
+		query.Set(param, next)
+	default:
+		return "", fmt.Errorf("unknown pagination type [%s]", pagination.Type)
+	}
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+func (crawler JsonApi) fetch(ctx context.Context, pageURL string) (interface{}, error) {
+	profile := crawler.conf.Profile
+	if profile == "" {
+		profile = "api"
+	}
+	resp, err := fetcher.Get(ctx, fetcher.Request{
+		URL:     pageURL,
+		Profile: profile,
+		Headers: crawler.conf.Headers,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var doc interface{}
+	decoder := json.NewDecoder(bytes.NewReader(resp.Body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// article 按字段映射提取单篇文章。
+func (crawler JsonApi) article(item interface{}) (register.Article, error) {
+	article := register.Article{
+		Title:  strings.TrimSpace(lookupString(item, crawler.conf.Title)),
+		Source: crawler.conf.Name,
+	}
+	if crawler.conf.Author != "" {
+		article.Author = lookupString(item, crawler.conf.Author)
+	}
+	if crawler.conf.Summary != "" {
+		article.Summary = lookupString(item, crawler.conf.Summary)
+	}
+	if crawler.conf.Tags != "" {
+		article.Tags = lookupStrings(item, crawler.conf.Tags)
+	}
+
+	if crawler.conf.LinkTemplate != "" {
+		article.URL = placeholder.ReplaceAllStringFunc(crawler.conf.LinkTemplate, func(s string) string {
+			return lookupString(item, s[1:len(s)-1])
+		})
+	} else {
+		article.URL = lookupString(item, crawler.conf.Link)
+	}
+	if article.URL == "" {
+		return article, errors.New("no link")
+	}
+
+	if crawler.conf.Date != "" {
+		t, err := crawler.published(lookupString(item, crawler.conf.Date))
+		if err != nil {
+			return article, err
+		}
+		article.Published = t
+	}
+	return article, nil
+}
+
+// published 按 layout 解析发布时间。
+func (crawler JsonApi) published(raw string) (time.Time, error) {
+	if raw == "" {
+		return time.Time{}, errors.New("no published time")
+	}
+	switch crawler.conf.Layout {
+	case "unix", "unixms":
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if crawler.conf.Layout == "unixms" {
+			return time.UnixMilli(int64(n)), nil
+		}
+		return time.Unix(int64(n), 0), nil
+	}
+
+	loc := Location
+	if crawler.conf.Timezone != "" {
+		var err error
+		loc, err = time.LoadLocation(crawler.conf.Timezone)
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
+	layout := crawler.conf.Layout
+	if layout == "" {
+		layout = time.RFC3339
+	}
+	return time.ParseInLocation(layout, raw, loc)
+}
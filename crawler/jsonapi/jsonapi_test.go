@@ -0,0 +1,92 @@
+package jsonapi
+
+import (
+	. "SecCrawler/config"
+	"SecCrawler/fetcher"
+	"SecCrawler/register"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGetWithoutDate(t *testing.T) {
+	var mu sync.Mutex
+	var pages []int // 按顺序请求的页码
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		mu.Lock()
+		pages = append(pages, page)
+		mu.Unlock()
+		// 共 3 页，每页 15 篇，从新到旧排列
+		fmt.Fprint(w, `{"data":[`)
+		for i := 0; i < 15 && page < 3; i++ {
+			if i > 0 {
+				fmt.Fprint(w, ",")
+			}
+			fmt.Fprintf(w, `{"title":"t","url":"https://a/%d"}`, page*15+i)
+		}
+		fmt.Fprint(w, `]}`)
+	}))
+	defer srv.Close()
+
+	Cfg = &Config{}
+	Location = time.UTC
+	fetcher.FetcherInit()
+
+	tests := []struct {
+		name   string
+		limit  int
+		cursor register.Cursor
+		pages  []int
+		want   []string
+	}{
+		{"first run", 0, register.Cursor{}, []int{0}, urls(0, register.FirstRunLimit)},
+		{"first run with smaller limit", 3, register.Cursor{}, []int{0}, urls(0, 3)},
+		{"first run with larger limit", 50, register.Cursor{}, []int{0}, urls(0, register.FirstRunLimit)},
+		{"stop at cursor", 0, register.Cursor{ID: "https://a/20"}, []int{0, 1}, urls(0, 20)},
+		{"cursor not found", 0, register.Cursor{ID: "https://a/100"}, []int{0, 1, 2, 3}, urls(0, 45)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pages = nil
+			crawler := New(JsonApiStruct{
+				Name:       "Test",
+				URL:        srv.URL,
+				Items:      "data",
+				Title:      "title",
+				Link:       "url",
+				Pagination: JsonPaginationStruct{Type: "page"},
+				Limit:      tt.limit,
+			})
+			articles, err := crawler.Get(context.Background(), tt.cursor)
+			if err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+			var got []string
+			for _, article := range articles {
+				got = append(got, article.URL)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("articles = %q, want %q", got, tt.want)
+			}
+			if !reflect.DeepEqual(pages, tt.pages) {
+				t.Errorf("requested pages = %v, want %v", pages, tt.pages)
+			}
+		})
+	}
+}
+
+// urls 返回第 from 篇到第 to 篇（不含）文章的链接。
+func urls(from, to int) []string {
+	var list []string
+	for i := from; i < to; i++ {
+		list = append(list, fmt.Sprintf("https://a/%d", i))
+	}
+	return list
+}
@@ -0,0 +1,108 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// lookup 按 JSONPath 风格的路径取值，支持 a.b、a[0]、a[*] 和 a['b.c']，开头的 $ 可省略。
+// 路径中包含 [*] 时可能返回多个值，路径不存在时返回空。
+func lookup(v interface{}, path string) []interface{} {
+	values := []interface{}{v}
+	for _, token := range tokenize(path) {
+		var next []interface{}
+		for _, value := range values {
+			switch node := value.(type) {
+			case map[string]interface{}:
+				if token == "*" {
+					for _, child := range node {
+						next = append(next, child)
+					}
+				} else if child, ok := node[token]; ok {
+					next = append(next, child)
+				}
+			case []interface{}:
+				if token == "*" {
+					next = append(next, node...)
+				} else if i, err := strconv.Atoi(token); err == nil {
+					if i < 0 {
+						i += len(node)
+					}
+					if i >= 0 && i < len(node) {
+						next = append(next, node[i])
+					}
+				}
+			}
+		}
+		values = next
+	}
+	return values
+}
+
+// lookupString 返回路径上第一个值的字符串形式。
+func lookupString(v interface{}, path string) string {
+	values := lookup(v, path)
+	if len(values) == 0 {
+		return ""
+	}
+	return toString(values[0])
+}
+
+// lookupStrings 返回路径上所有值的字符串形式，列表会被展开。
+func lookupStrings(v interface{}, path string) []string {
+	var result []string
+	for _, value := range lookup(v, path) {
+		if list, ok := value.([]interface{}); ok {
+			for _, item := range list {
+				result = append(result, toString(item))
+			}
+			continue
+		}
+		result = append(result, toString(value))
+	}
+	return result
+}
+
+func tokenize(path string) []string {
+	path = strings.TrimPrefix(strings.TrimSpace(path), "$")
+	var tokens []string
+	for len(path) > 0 {
+		switch path[0] {
+		case '.':
+			path = path[1:]
+		case '[':
+			end := strings.IndexByte(path, ']')
+			if end < 0 {
+				end = len(path)
+				path += "]"
+			}
+			tokens = append(tokens, strings.Trim(path[1:end], `'"`))
+			path = path[end+1:]
+		default:
+			end := strings.IndexAny(path, ".[")
+			if end < 0 {
+				end = len(path)
+			}
+			tokens = append(tokens, path[:end])
+			path = path[end:]
+		}
+	}
+	return tokens
+}
+
+func toString(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case json.Number:
+		return value.String()
+	case bool:
+		return strconv.FormatBool(value)
+	default:
+		b, _ := json.Marshal(value)
+		return string(b)
+	}
+}
@@ -0,0 +1,210 @@
+package jsonapi
+
+import (
+	. "SecCrawler/config"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+const testDoc = `{
+	"data": {
+		"items": [
+			{"title": "a", "id": 1, "tags": ["web", "rce"], "meta": {"level": "high"}},
+			{"title": "b", "id": 2, "tags": [], "meta": {"level": "low"}},
+			{"title": "c", "id": 3, "meta": {"level": "high"}}
+		],
+		"matrix": [[1, 2], [3, [4, 5]]],
+		"dotted.key": "dot",
+		"ok": true,
+		"empty": null
+	}
+}`
+
+// decode 与抓取时相同，数字解析为 json.Number。
+func decode(t *testing.T, doc string) interface{} {
+	t.Helper()
+	decoder := json.NewDecoder(strings.NewReader(doc))
+	decoder.UseNumber()
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	return v
+}
+
+func TestLookup(t *testing.T) {
+	doc := decode(t, testDoc)
+	tests := []struct {
+		name string
+		path string
+		want []string
+	}{
+		{"field", "data.items[0].title", []string{"a"}},
+		{"leading $", "$.data.items[0].title", []string{"a"}},
+		{"leading $ with bracket", "$['data']['items'][1]['title']", []string{"b"}},
+		{"spaces", "  $.data.ok ", []string{"true"}},
+		{"negative index", "data.items[-1].title", []string{"c"}},
+		{"index out of range", "data.items[3].title", nil},
+		{"wildcard", "data.items[*].title", []string{"a", "b", "c"}},
+		{"wildcard skips missing", "data.items[*].tags[0]", []string{"web"}},
+		{"wildcard then field", "data.items[*].meta.level", []string{"high", "low", "high"}},
+		{"number", "data.items[*].id", []string{"1", "2", "3"}},
+		{"nested array index", "data.matrix[1][1][0]", []string{"4"}},
+		{"nested array wildcard", "data.matrix[*][0]", []string{"1", "3"}},
+		{"nested array double wildcard", "data.matrix[*][*]", []string{"1", "2", "3", "[4,5]"}},
+		{"quoted key with dot", "data['dotted.key']", []string{"dot"}},
+		{"double quoted key", `data["dotted.key"]`, []string{"dot"}},
+		{"unclosed bracket", "data.items[0", []string{`{"id":1,"meta":{"level":"high"},"tags":["web","rce"],"title":"a"}`}},
+		{"null", "data.empty", []string{""}},
+		{"missing", "data.missing.title", nil},
+		{"index on object", "data[0]", nil},
+		{"field on array", "data.items.title", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, value := range lookup(doc, tt.path) {
+				got = append(got, toString(value))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("lookup(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLookupObjectWildcard(t *testing.T) {
+	doc := decode(t, `{"a": {"x": {"v": 1}, "y": {"v": 2}}}`)
+	var got []string
+	for _, value := range lookup(doc, "$.a[*].v") {
+		got = append(got, toString(value))
+	}
+	// map 的遍历顺序不固定
+	sort.Strings(got)
+	if want := []string{"1", "2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("lookup = %q, want %q", got, want)
+	}
+}
+
+func TestLookupStrings(t *testing.T) {
+	doc := decode(t, testDoc)
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"data.items[0].tags", []string{"web", "rce"}},
+		{"data.items[*].tags", []string{"web", "rce"}},
+		{"data.items[0].title", []string{"a"}},
+		{"data.missing", nil},
+	}
+	for _, tt := range tests {
+		if got := lookupStrings(doc, tt.path); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("lookupStrings(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+	if got := lookupString(doc, "data.items[*].title"); got != "a" {
+		t.Errorf("lookupString = %q, want %q", got, "a")
+	}
+}
+
+func TestFilters(t *testing.T) {
+	items := lookup(decode(t, testDoc), "data.items[*]")
+	tests := []struct {
+		name    string
+		include []JsonFilterStruct
+		exclude []JsonFilterStruct
+		want    []string // 保留的文章标题
+	}{
+		{
+			name: "no filters",
+			want: []string{"a", "b", "c"},
+		},
+		{
+			name:    "include values",
+			include: []JsonFilterStruct{{Field: "meta.level", Values: []string{"high"}}},
+			want:    []string{"a", "c"},
+		},
+		{
+			name:    "include any value of a list",
+			include: []JsonFilterStruct{{Field: "tags", Values: []string{"rce"}}},
+			want:    []string{"a"},
+		},
+		{
+			name:    "include pattern",
+			include: []JsonFilterStruct{{Field: "title", Pattern: "^[bc]$"}},
+			want:    []string{"b", "c"},
+		},
+		{
+			name:    "include values or pattern",
+			include: []JsonFilterStruct{{Field: "title", Values: []string{"a"}, Pattern: "c"}},
+			want:    []string{"a", "c"},
+		},
+		{
+			name: "include requires all filters",
+			include: []JsonFilterStruct{
+				{Field: "meta.level", Values: []string{"high"}},
+				{Field: "id", Values: []string{"3"}},
+			},
+			want: []string{"c"},
+		},
+		{
+			name:    "include missing field",
+			include: []JsonFilterStruct{{Field: "missing", Pattern: ".*"}},
+			want:    nil,
+		},
+		{
+			name:    "exclude values",
+			exclude: []JsonFilterStruct{{Field: "meta.level", Values: []string{"low"}}},
+			want:    []string{"a", "c"},
+		},
+		{
+			name: "exclude any filter",
+			exclude: []JsonFilterStruct{
+				{Field: "title", Values: []string{"a"}},
+				{Field: "id", Values: []string{"3"}},
+			},
+			want: []string{"b"},
+		},
+		{
+			name:    "exclude empty list and missing field",
+			exclude: []JsonFilterStruct{{Field: "tags", Pattern: ".*"}},
+			want:    []string{"b", "c"},
+		},
+		{
+			name:    "include and exclude",
+			include: []JsonFilterStruct{{Field: "meta.level", Values: []string{"high"}}},
+			exclude: []JsonFilterStruct{{Field: "tags", Values: []string{"web"}}},
+			want:    []string{"c"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			include, err := compileFilters(tt.include)
+			if err != nil {
+				t.Fatalf("compile include: %v", err)
+			}
+			exclude, err := compileFilters(tt.exclude)
+			if err != nil {
+				t.Fatalf("compile exclude: %v", err)
+			}
+			var got []string
+			for _, item := range items {
+				if matchAll(item, include) && !matchAny(item, exclude) {
+					got = append(got, lookupString(item, "title"))
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("kept %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileFiltersInvalidPattern(t *testing.T) {
+	if _, err := compileFilters([]JsonFilterStruct{{Field: "title", Pattern: "("}}); err == nil {
+		t.Error("compileFilters with invalid pattern: want error")
+	}
+}
@@ -0,0 +1,37 @@
+package crawler
+
+import (
+	. "SecCrawler/config"
+)
+
+// builtinJsonApis 内置的 JSON 接口，沿用各自原有的配置项开关。
+func builtinJsonApis() []JsonApiStruct {
+	return []JsonApiStruct{
+		{
+			Enabled:      Cfg.Crawler.HuoxianZone.Enabled,
+			Name:         "火线Zone",
+			Description:  "全部主题 - 火线 Zone-安全攻防社区",
+			URL:          "https://zone.huoxian.cn/api/discussions?include=tags&sort=-createdAt",
+			Items:        "data",
+			Title:        "attributes.title",
+			LinkTemplate: "https://zone.huoxian.cn/d/{attributes.slug}",
+			Date:         "attributes.lastPostedAt",
+			// 只推送技术文章（标签 4），跳过官方公告（标签 2），同时避免置顶文章影响时间判断
+			Include: []JsonFilterStruct{
+				{Field: "relationships.tags.data[*].id", Values: []string{"4"}},
+			},
+			Exclude: []JsonFilterStruct{
+				{Field: "relationships.tags.data[*].id", Values: []string{"2"}},
+			},
+			Pagination: JsonPaginationStruct{
+				Type:     "offset",
+				Param:    "page[offset]",
+				MaxPages: 10,
+			},
+			Headers: map[string]string{
+				"referer": "https://zone.huoxian.cn/?sort=newest",
+			},
+			CrawlScheduleStruct: Cfg.Crawler.HuoxianZone.CrawlScheduleStruct,
+		},
+	}
+}
@@ -2,9 +2,16 @@ package lab
 
 import (
 	. "SecCrawler/config"
+	"SecCrawler/crawler/feed"
+	"SecCrawler/fetcher"
 	"SecCrawler/register"
+	"SecCrawler/store"
+	"context"
 	"errors"
+	"fmt"
 	"log"
+	"strings"
+	"time"
 )
 
 type Lab struct{}
@@ -13,52 +20,73 @@ func (crawler Lab) Config() register.CrawlerConfig {
 	return register.CrawlerConfig{
 		Name:        "Lab",
 		Description: "实验室文章",
+		Interval:    Cfg.Crawler.Lab.Interval,
+		Cron:        Cfg.Crawler.Lab.Cron,
 	}
 }
 
-// Get 获取 Lab 前24小时内文章。
-func (crawler Lab) Get() ([][]string, error) {
-	var resultSlice [][]string
+// Get 获取各实验室博客水位线之后的新文章。每个博客使用独立的水位线，
+// 由调度方按文章的 Source 保存，抓取失败的博客下次仍从原来的水位线开始。
+// 没有抓取到文章且有博客抓取失败时返回这些错误，以免失败被记录为成功。
+func (crawler Lab) Get(ctx context.Context, cursor register.Cursor) ([]register.Article, error) {
+	var resultSlice []register.Article
+	var errs []string
 
-	if Cfg.Crawler.Lab.NoahLab.Enabled {
-		resultSlice = tmpCrawler(resultSlice, NoahLab{})
-	}
-	if Cfg.Crawler.Lab.Blog360.Enabled {
-		resultSlice = tmpCrawler(resultSlice, Blog360{})
-	}
-	if Cfg.Crawler.Lab.Nsfocus.Enabled {
-		resultSlice = tmpCrawler(resultSlice, Nsfocus{})
-	}
-	if Cfg.Crawler.Lab.Xlab.Enabled {
-		resultSlice = tmpCrawler(resultSlice, Xlab{})
-	}
-	if Cfg.Crawler.Lab.AlphaLab.Enabled {
-		resultSlice = tmpCrawler(resultSlice, AlphaLab{})
-	}
-	if Cfg.Crawler.Lab.Netlab.Enabled {
-		resultSlice = tmpCrawler(resultSlice, Netlab{})
-	}
-	if Cfg.Crawler.Lab.RiskivyBlog.Enabled {
-		resultSlice = tmpCrawler(resultSlice, RiskivyBlog{})
-	}
-	if Cfg.Crawler.Lab.TSRCBlog.Enabled {
-		resultSlice = tmpCrawler(resultSlice, TSRCBlog{})
-	}
-	if Cfg.Crawler.Lab.X1cT34m.Enabled {
-		resultSlice = tmpCrawler(resultSlice, X1cT34m{})
+	for _, conf := range labFeeds() {
+		if !conf.Enabled {
+			continue
+		}
+		sub, err := store.GetCursor(register.SubCursorKey(crawler.Config().Name, conf.Name))
+		if err != nil {
+			log.Printf("load cursor [%s] error: %s\n", conf.Name, err.Error())
+			errs = append(errs, fmt.Sprintf("[%s] %s", conf.Name, err.Error()))
+			continue
+		}
+		if sub.Published.IsZero() {
+			sub.Published = time.Now().Add(-Cfg.Crawler.MaxLookback)
+		}
+		resultSlice, err = tmpCrawler(ctx, resultSlice, sub, feed.New(conf))
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("[%s] %s", conf.Name, err.Error()))
+		}
 	}
 
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
 	if len(resultSlice) == 0 {
-		return nil, errors.New("no records in the last 24 hours")
+		if len(errs) > 0 {
+			return nil, errors.New(strings.Join(errs, "; "))
+		}
+		return nil, register.ErrNoRecords
 	}
 	return resultSlice, nil
 }
 
-func tmpCrawler(s [][]string, crawler register.Crawler) [][]string {
-	crawlerResult, err := crawler.Get()
-	if err != nil {
+// tmpCrawler 抓取单个博客，只保留抓取成功的博客的条件请求校验信息，抓取失败时返回错误。
+func tmpCrawler(ctx context.Context, s []register.Article, cursor register.Cursor, crawler register.Crawler) ([]register.Article, error) {
+	subCtx, validators := fetcher.WithValidators(ctx)
+	crawlerResult, err := crawler.Get(subCtx, cursor)
+	if err != nil && !errors.Is(err, register.ErrNoRecords) {
 		log.Printf("crawl [%s] error: %s\n\n", crawler.Config().Name, err.Error())
+		return s, err
 	}
+	validators.Keep(ctx)
 	s = append(s, crawlerResult...)
-	return s
+	return s, nil
+}
+
+// labFeeds 各实验室博客的订阅源。
+func labFeeds() []FeedStruct {
+	return []FeedStruct{
+		{Enabled: Cfg.Crawler.Lab.NoahLab.Enabled, Name: "Lab.NoahLab", Description: "Noah Lab-Focus on Advanced Attacks and Defenses", URL: "http://noahblog.360.cn/rss/"},
+		{Enabled: Cfg.Crawler.Lab.Blog360.Enabled, Name: "Lab.Blog360", Description: "360核心安全技术博客", URL: "https://blogs.360.net/rss.html"},
+		{Enabled: Cfg.Crawler.Lab.Nsfocus.Enabled, Name: "Lab.Nsfocus", Description: "绿盟科技技术博客", URL: "http://blog.nsfocus.net/feed/"},
+		{Enabled: Cfg.Crawler.Lab.Xlab.Enabled, Name: "Lab.Xlab", Description: "腾讯安全玄武实验室", URL: "https://xlab.tencent.com/cn/atom.xml"},
+		{Enabled: Cfg.Crawler.Lab.AlphaLab.Enabled, Name: "Lab.AlphaLab", Description: "天融信阿尔法实验室", URL: "http://blog.topsec.com.cn/feed/"},
+		{Enabled: Cfg.Crawler.Lab.Netlab.Enabled, Name: "Lab.Netlab", Description: "360 Netlab Blog-Network Security Research Lab", URL: "http://blog.topsec.com.cn/feed/"},
+		{Enabled: Cfg.Crawler.Lab.RiskivyBlog.Enabled, Name: "Lab.RiskivyBlog", Description: "斗象能力中心", URL: "https://blog.riskivy.com/feed/"},
+		{Enabled: Cfg.Crawler.Lab.TSRCBlog.Enabled, Name: "Lab.TSRCBlog", Description: "腾讯安全应急响应中心", URL: "https://security.tencent.com/index.php/feed/blog/0", Layout: "2006-01-02 15:04:05", Timezone: "Asia/Shanghai"},
+		{Enabled: Cfg.Crawler.Lab.X1cT34m.Enabled, Name: "Lab.X1cT34m", Description: "X1cT34m - 南京邮电大学", URL: "https://ctf.njupt.edu.cn/feed"},
+	}
 }
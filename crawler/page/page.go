@@ -0,0 +1,178 @@
+package page
+
+import (
+	. "SecCrawler/config"
+	"SecCrawler/fetcher"
+	"SecCrawler/register"
+	"SecCrawler/utils"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Page 通用的 HTML 列表页爬虫，列表页结构由 PageStruct 中的 CSS 选择器描述。
+type Page struct {
+	conf PageStruct
+}
+
+func New(conf PageStruct) *Page {
+	return &Page{conf: conf}
+}
+
+func (crawler Page) Config() register.CrawlerConfig {
+	return register.CrawlerConfig{
+		Name:        crawler.conf.Name,
+		Description: crawler.conf.Description,
+		Interval:    crawler.conf.Interval,
+		Cron:        crawler.conf.Cron,
+	}
+}
+
+// Get 获取列表页水位线之后的新文章。
+func (crawler Page) Get(ctx context.Context, cursor register.Cursor) ([]register.Article, error) {
+	base, err := url.Parse(crawler.conf.URL)
+	if crawler.conf.BaseURL != "" {
+		base, err = url.Parse(crawler.conf.BaseURL)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := fetcher.Get(ctx, fetcher.Request{
+		URL:     crawler.conf.URL,
+		Profile: crawler.conf.Profile,
+		Headers: crawler.conf.Headers,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(resp.Body))
+	if err != nil {
+		return nil, err
+	}
+
+	limit := crawler.conf.Limit
+	if crawler.conf.Date == "" && cursor.ID == "" {
+		// 首次运行没有上次抓取的文章可以作为停止位置，只抓取最新的几篇
+		if limit <= 0 || limit > register.FirstRunLimit {
+			limit = register.FirstRunLimit
+		}
+	}
+
+	var resultSlice []register.Article
+	fmt.Printf("[*] [%s] crawler result:\n%s\n\n", crawler.conf.Name, utils.CurrentTime())
+
+	items := doc.Find(crawler.conf.Item)
+	if items.Length() == 0 {
+		return nil, fmt.Errorf("no element matches item selector [%s]", crawler.conf.Item)
+	}
+	items.EachWithBreak(func(_ int, item *goquery.Selection) bool {
+		title := text(pick(item, crawler.conf.Title))
+		link, err := crawler.link(item, base)
+		if err != nil {
+			log.Printf("[%s] skip item [%s]: %s\n", crawler.conf.Name, title, err.Error())
+			return true
+		}
+
+		var t time.Time
+		if crawler.conf.Date == "" {
+			// 列表不提供发布时间时按从新到旧排列处理，遇到上次抓取的最新文章即停止
+			if link == cursor.ID {
+				return false
+			}
+		} else {
+			t, err = crawler.published(item)
+			if err != nil {
+				log.Printf("[%s] skip item [%s]: %s\n", crawler.conf.Name, title, err.Error())
+				return true
+			}
+			// 列表中可能存在置顶的旧文章，因此不在遇到旧文章时提前结束
+			if !cursor.IsNew(t) {
+				return true
+			}
+			fmt.Println(t.In(Location).Format("2006/01/02 15:04:05"))
+		}
+
+		fmt.Println(title)
+		fmt.Printf("%s\n\n", link)
+
+		resultSlice = append(resultSlice, register.Article{
+			URL:       link,
+			Title:     title,
+			Published: t,
+			Source:    crawler.conf.Name,
+		})
+		return limit <= 0 || len(resultSlice) < limit
+	})
+
+	if len(resultSlice) == 0 {
+		return nil, register.ErrNoRecords
+	}
+	return resultSlice, nil
+}
+
+// link 提取文章链接，并将相对链接补全为绝对链接。
+func (crawler Page) link(item *goquery.Selection, base *url.URL) (string, error) {
+	attr := crawler.conf.LinkAttr
+	if attr == "" {
+		attr = "href"
+	}
+	raw, ok := pick(item, crawler.conf.Link).Attr(attr)
+	raw = strings.TrimSpace(raw)
+	if !ok || raw == "" {
+		return "", fmt.Errorf("no link in attribute [%s]", attr)
+	}
+	ref, err := url.Parse(raw)
+	if err != nil {
+		return "", err
+	}
+	return base.ResolveReference(ref).String(), nil
+}
+
+// published 按 layout 解析文章的发布时间。
+func (crawler Page) published(item *goquery.Selection) (time.Time, error) {
+	date := pick(item, crawler.conf.Date)
+	raw := text(date)
+	if crawler.conf.DateAttr != "" {
+		raw, _ = date.Attr(crawler.conf.DateAttr)
+		raw = strings.TrimSpace(raw)
+	}
+	if raw == "" {
+		return time.Time{}, errors.New("no published time")
+	}
+
+	loc := Location
+	if crawler.conf.Timezone != "" {
+		var err error
+		loc, err = time.LoadLocation(crawler.conf.Timezone)
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
+	layout := crawler.conf.Layout
+	if layout == "" {
+		layout = time.RFC3339
+	}
+	return time.ParseInLocation(layout, raw, loc)
+}
+
+// pick 返回 item 内第一个匹配 selector 的元素，selector 为空时返回 item 本身。
+func pick(item *goquery.Selection, selector string) *goquery.Selection {
+	if selector == "" {
+		return item
+	}
+	return item.Find(selector).First()
+}
+
+// text 返回元素的文本，并合并连续的空白字符。
+func text(s *goquery.Selection) string {
+	return strings.Join(strings.Fields(s.Text()), " ")
+}
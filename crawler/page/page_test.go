@@ -0,0 +1,70 @@
+package page
+
+import (
+	. "SecCrawler/config"
+	"SecCrawler/fetcher"
+	"SecCrawler/register"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGetWithoutDate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 30 篇文章，从新到旧排列，使用相对链接
+		fmt.Fprint(w, "<html><body>")
+		for i := 0; i < 30; i++ {
+			fmt.Fprintf(w, `<article><h2><a href="/post/%d">t</a></h2></article>`, i)
+		}
+		fmt.Fprint(w, "</body></html>")
+	}))
+	defer srv.Close()
+
+	Cfg = &Config{}
+	Location = time.UTC
+	fetcher.FetcherInit()
+
+	tests := []struct {
+		name   string
+		limit  int
+		cursor register.Cursor
+		want   int // 抓取到的文章数，均为最新的几篇
+	}{
+		{"first run", 0, register.Cursor{}, register.FirstRunLimit},
+		{"first run with smaller limit", 3, register.Cursor{}, 3},
+		{"first run with larger limit", 50, register.Cursor{}, register.FirstRunLimit},
+		{"stop at cursor", 0, register.Cursor{ID: srv.URL + "/post/20"}, 20},
+		{"cursor not found", 0, register.Cursor{ID: srv.URL + "/post/100"}, 30},
+		{"limit with cursor", 5, register.Cursor{ID: srv.URL + "/post/20"}, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			crawler := New(PageStruct{
+				Name:  "Test",
+				URL:   srv.URL,
+				Item:  "article",
+				Title: "h2 a",
+				Link:  "h2 a",
+				Limit: tt.limit,
+			})
+			articles, err := crawler.Get(context.Background(), tt.cursor)
+			if err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+			var got, want []string
+			for _, article := range articles {
+				got = append(got, article.URL)
+			}
+			for i := 0; i < tt.want; i++ {
+				want = append(want, fmt.Sprintf("%s/post/%d", srv.URL, i))
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("articles = %q, want %q", got, want)
+			}
+		})
+	}
+}
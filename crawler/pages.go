@@ -0,0 +1,38 @@
+package crawler
+
+import (
+	. "SecCrawler/config"
+)
+
+// builtinPages 内置的 HTML 列表页，沿用各自原有的配置项开关。
+func builtinPages() []PageStruct {
+	return []PageStruct{
+		{
+			Enabled:             Cfg.Crawler.Anquanke.Enabled,
+			Name:                "Anquanke",
+			Description:         "安全客-安全资讯平台",
+			URL:                 "https://www.anquanke.com/knowledge",
+			Item:                "div.article-item",
+			Title:               "div.title a",
+			Link:                "div.title a",
+			Date:                "span:has(i.fa-clock-o)",
+			Layout:              "2006-01-02 15:04:05",
+			Timezone:            "Asia/Shanghai",
+			CrawlScheduleStruct: Cfg.Crawler.Anquanke.CrawlScheduleStruct,
+		},
+		{
+			// 棱角社区列表不提供发布时间，按上次抓取的最新文章判断新文章
+			Enabled:     Cfg.Crawler.EdgeForum.Enabled,
+			Name:        "EdgeForum",
+			Description: "棱角社区攻防日报",
+			URL:         "https://forum.ywhack.com/forumdisplay.php?fid=59&orderby=lastpost&filter=86400",
+			Item:        ":haschild(small.card-subtitle)",
+			Title:       `a[target="_blank"]`,
+			Link:        `a[target="_blank"]`,
+			Headers: map[string]string{
+				"Cache-Control": "max-age=0",
+			},
+			CrawlScheduleStruct: Cfg.Crawler.EdgeForum.CrawlScheduleStruct,
+		},
+	}
+}
@@ -55,17 +55,22 @@ func (x X) Config() register.CrawlerConfig {
 	return register.CrawlerConfig{
 		Name:        "SocialMedia.X",
 		Description: "X(Twitter)平台安全情报聚合",
+		Interval:    config.Cfg.Crawler.SocialMedia.X.Interval,
+		Cron:        config.Cfg.Crawler.SocialMedia.X.Cron,
 	}
 }
 
-// Get 获取X平台前24小时内推文
-func (x X) Get() ([][]string, error) {
+// Get 获取X平台水位线之后的新推文
+func (x X) Get(ctx context.Context, cursor register.Cursor) ([]register.Article, error) {
 	// 优先尝试使用 x-kit (基于 Cookie 的爬虫)
 	fmt.Println("[*] 尝试使用 x-kit (Cookie 爬虫)...")
-	tweets, err := x.fetchWithXKit()
+	tweets, err := x.fetchWithXKit(ctx, cursor)
 	if err == nil {
 		return tweets, nil
 	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
 	fmt.Printf("[!] x-kit 调用失败: %v，尝试其他方案...\n", err)
 
 	// API v2 needs a bearer token. In the free plan, this is often the same as the access token.
@@ -73,21 +78,21 @@ func (x X) Get() ([][]string, error) {
 
 	if bearerToken != "" {
 		fmt.Println("[*] 尝试使用 Twitter API V2...")
-		tweets, err := x.fetchWithAPIV2(bearerToken)
+		tweets, err := x.fetchWithAPIV2(ctx, bearerToken, cursor)
 		if err != nil {
 			fmt.Printf("[!] API V2 调用失败: %v，切换到免费方案...\n", err)
-			return x.fetchWithScraper()
+			return x.fetchWithScraper(ctx, cursor)
 		}
 		return tweets, nil
 	}
 
 	fmt.Println("[*] 未配置 API V2 的 Bearer/Access Token，使用免费爬虫方案...")
-	return x.fetchWithScraper()
+	return x.fetchWithScraper(ctx, cursor)
 }
 
 // fetchWithXKit 使用 x-kit 脚本获取推文
-func (x X) fetchWithXKit() ([][]string, error) {
-	var resultSlice [][]string
+func (x X) fetchWithXKit(ctx context.Context, cursor register.Cursor) ([]register.Article, error) {
+	var resultSlice []register.Article
 	targetUsers := getTargetUsers()
 	fmt.Printf("[*] 使用 x-kit 监控 %d 个用户账号\n", len(targetUsers))
 
@@ -98,7 +103,7 @@ func (x X) fetchWithXKit() ([][]string, error) {
 		fmt.Printf("[*] 正在使用 x-kit 爬取 @%s...\n", username)
 
 		// 执行 bun run scripts/crawl-user.ts <username>
-		cmd := exec.Command("bun", "run", "scripts/crawl-user.ts", username)
+		cmd := exec.CommandContext(ctx, "bun", "run", "scripts/crawl-user.ts", username)
 		cmd.Dir = xKitPath
 		output, err := cmd.CombinedOutput()
 		if err != nil {
@@ -108,9 +113,13 @@ func (x X) fetchWithXKit() ([][]string, error) {
 			// 检测是否触发限速
 			if strings.Contains(outputStr, "429") || strings.Contains(outputStr, "Too Many Requests") {
 				fmt.Println("[!] 检测到 429 限速，暂停 2 分钟等待恢复...")
-				time.Sleep(120 * time.Second)
+				if err := utils.Sleep(ctx, 120*time.Second); err != nil {
+					return nil, err
+				}
 			} else {
-				time.Sleep(5 * time.Second)
+				if err := utils.Sleep(ctx, 5*time.Second); err != nil {
+					return nil, err
+				}
 			}
 			continue
 		}
@@ -129,7 +138,9 @@ func (x X) fetchWithXKit() ([][]string, error) {
 		startIndex := strings.Index(outputStr, "[")
 		if startIndex == -1 {
 			fmt.Printf("[!] x-kit 输出中未找到 JSON 数组: %s\n", outputStr)
-			time.Sleep(5 * time.Second)
+			if err := utils.Sleep(ctx, 5*time.Second); err != nil {
+				return nil, err
+			}
 			continue
 		}
 		jsonPart := []byte(outputStr[startIndex:])
@@ -138,7 +149,9 @@ func (x X) fetchWithXKit() ([][]string, error) {
 		if err := json.Unmarshal(jsonPart, &tweets); err != nil {
 			// 尝试解析错误信息，如果输出不是 JSON
 			fmt.Printf("[!] 解析 @%s 的 x-kit 输出失败: %v\n原始输出: %s\n", username, err, string(output))
-			time.Sleep(5 * time.Second)
+			if err := utils.Sleep(ctx, 5*time.Second); err != nil {
+				return nil, err
+			}
 			continue
 		}
 
@@ -157,25 +170,33 @@ func (x X) fetchWithXKit() ([][]string, error) {
 				continue
 			}
 
-			if !utils.IsIn24Hours(tweetTime) {
+			if !cursor.IsNew(tweetTime) {
 				continue
 			}
 
-			resultSlice = append(resultSlice, []string{tweet.PermanentURL, fmt.Sprintf("@%s: %s", tweet.Username, tweet.Text)})
+			resultSlice = append(resultSlice, register.Article{
+				URL:       tweet.PermanentURL,
+				Title:     fmt.Sprintf("@%s: %s", tweet.Username, tweet.Text),
+				Published: tweetTime,
+				Author:    tweet.Username,
+				Source:    x.Config().Name,
+			})
 		}
 		// 正常请求间隔增加到 15 秒
-		time.Sleep(15 * time.Second)
+		if err := utils.Sleep(ctx, 15*time.Second); err != nil {
+			return nil, err
+		}
 	}
 
 	if len(resultSlice) == 0 {
-		return nil, errors.New("x-kit 未找到24小时内记录")
+		return nil, errors.New("x-kit 未找到新记录")
 	}
 
 	return resultSlice, nil
 }
 
 // fetchWithAPIV2 使用官方API V2获取推文
-func (x X) fetchWithAPIV2(token string) ([][]string, error) {
+func (x X) fetchWithAPIV2(ctx context.Context, token string, cursor register.Cursor) ([]register.Article, error) {
 	client := &twitter.Client{
 		Authorizer: &authorizer{
 			Token: token,
@@ -184,7 +205,7 @@ func (x X) fetchWithAPIV2(token string) ([][]string, error) {
 		Host:   "https://api.twitter.com",
 	}
 
-	var resultSlice [][]string
+	var resultSlice []register.Article
 	targetUsers := getTargetUsers()
 	fmt.Printf("[*] 使用 API V2 监控 %d 个用户账号\n", len(targetUsers))
 
@@ -192,7 +213,7 @@ func (x X) fetchWithAPIV2(token string) ([][]string, error) {
 		fmt.Printf("[*] 正在使用 API V2 爬取 @%s...\n", username)
 
 		// 1. 通过用户名获取用户ID
-		userResp, err := client.UserNameLookup(context.Background(), []string{username}, twitter.UserLookupOpts{})
+		userResp, err := client.UserNameLookup(ctx, []string{username}, twitter.UserLookupOpts{})
 		if err != nil {
 			// Check for specific API errors returned in the response body
 			if userResp != nil && len(userResp.Raw.Errors) > 0 {
@@ -200,12 +221,16 @@ func (x X) fetchWithAPIV2(token string) ([][]string, error) {
 			} else {
 				fmt.Printf("[!] 获取用户 @%s ID 失败: %v\n", username, err)
 			}
-			time.Sleep(2 * time.Second)
+			if err := utils.Sleep(ctx, 2*time.Second); err != nil {
+				return nil, err
+			}
 			continue
 		}
 		if len(userResp.Raw.Users) == 0 {
 			fmt.Printf("[!] 未找到用户 @%s\n", username)
-			time.Sleep(2 * time.Second)
+			if err := utils.Sleep(ctx, 2*time.Second); err != nil {
+				return nil, err
+			}
 			continue
 		}
 		userID := userResp.Raw.Users[0].ID
@@ -215,46 +240,58 @@ func (x X) fetchWithAPIV2(token string) ([][]string, error) {
 			TweetFields: []twitter.TweetField{twitter.TweetFieldCreatedAt, twitter.TweetFieldText},
 			MaxResults:  10, // 获取最近10条
 		}
-		timeline, err := client.UserTweetTimeline(context.Background(), userID, opts)
+		timeline, err := client.UserTweetTimeline(ctx, userID, opts)
 		if err != nil {
 			if timeline != nil && len(timeline.Raw.Errors) > 0 {
 				fmt.Printf("[!] 获取 @%s 时间线失败: %s\n", username, timeline.Raw.Errors[0].Detail)
 			} else {
 				fmt.Printf("[!] 获取 @%s 时间线失败: %v\n", username, err)
 			}
-			time.Sleep(2 * time.Second)
+			if err := utils.Sleep(ctx, 2*time.Second); err != nil {
+				return nil, err
+			}
 			continue
 		}
 
 		// Check if there is any data
 		if timeline.Raw == nil || len(timeline.Raw.Tweets) == 0 {
 			fmt.Printf("[*] @%s 最近没有发布推文\n", username)
-			time.Sleep(2 * time.Second)
+			if err := utils.Sleep(ctx, 2*time.Second); err != nil {
+				return nil, err
+			}
 			continue
 		}
 
 		dictionaries := timeline.Raw.TweetDictionaries()
 		for _, tweetDict := range dictionaries {
 			tweetTime, _ := time.Parse(time.RFC3339, tweetDict.Tweet.CreatedAt)
-			if !utils.IsIn24Hours(tweetTime) {
+			if !cursor.IsNew(tweetTime) {
 				continue
 			}
 			permanentURL := fmt.Sprintf("https://twitter.com/%s/status/%s", username, tweetDict.Tweet.ID)
-			resultSlice = append(resultSlice, []string{permanentURL, fmt.Sprintf("@%s: %s", username, tweetDict.Tweet.Text)})
+			resultSlice = append(resultSlice, register.Article{
+				URL:       permanentURL,
+				Title:     fmt.Sprintf("@%s: %s", username, tweetDict.Tweet.Text),
+				Published: tweetTime,
+				Author:    username,
+				Source:    x.Config().Name,
+			})
+		}
+		if err := utils.Sleep(ctx, 2*time.Second); err != nil {
+			return nil, err
 		}
-		time.Sleep(2 * time.Second)
 	}
 
 	if len(resultSlice) == 0 {
-		return nil, errors.New("API V2 未找到24小时内记录")
+		return nil, errors.New("API V2 未找到新记录")
 	}
 
 	return resultSlice, nil
 }
 
 // fetchWithScraper 使用免费爬虫获取推文（无需API）
-func (x X) fetchWithScraper() ([][]string, error) {
-	var resultSlice [][]string
+func (x X) fetchWithScraper(ctx context.Context, cursor register.Cursor) ([]register.Article, error) {
+	var resultSlice []register.Article
 
 	// 创建 scraper 实例
 	scraper := twitterscraper.New()
@@ -279,14 +316,14 @@ func (x X) fetchWithScraper() ([][]string, error) {
 
 		// 获取用户推文
 		count := 0
-		for tweet := range scraper.GetTweets(context.Background(), username, 20) {
+		for tweet := range scraper.GetTweets(ctx, username, 20) {
 			if tweet.Error != nil {
 				fmt.Printf("[!] 获取 @%s 推文失败: %v\n", username, tweet.Error)
 				break
 			}
 
-			// 只收集24小时内的推文
-			if !utils.IsIn24Hours(tweet.TimeParsed) {
+			// 只收集水位线之后的推文
+			if !cursor.IsNew(tweet.TimeParsed) {
 				break
 			}
 
@@ -296,9 +333,13 @@ func (x X) fetchWithScraper() ([][]string, error) {
 			fmt.Printf("%s\n\n", tweet.PermanentURL)
 
 			// 添加到结果集
-			var s []string
-			s = append(s, tweet.PermanentURL, fmt.Sprintf("@%s: %s", username, tweet.Text))
-			resultSlice = append(resultSlice, s)
+			resultSlice = append(resultSlice, register.Article{
+				URL:       tweet.PermanentURL,
+				Title:     fmt.Sprintf("@%s: %s", username, tweet.Text),
+				Published: tweet.TimeParsed,
+				Author:    username,
+				Source:    x.Config().Name,
+			})
 
 			count++
 			if count >= 5 { // 每个用户最多取5条最新推文
@@ -307,11 +348,13 @@ func (x X) fetchWithScraper() ([][]string, error) {
 		}
 
 		// 避免请求过快被限制
-		time.Sleep(2 * time.Second)
+		if err := utils.Sleep(ctx, 2*time.Second); err != nil {
+			return nil, err
+		}
 	}
 
 	if len(resultSlice) == 0 {
-		return nil, errors.New("no records in the last 24 hours")
+		return nil, register.ErrNoRecords
 	}
 
 	return resultSlice, nil
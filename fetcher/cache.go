@@ -0,0 +1,73 @@
+package fetcher
+
+import (
+	. "SecCrawler/config"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// cacheEntry 缓存文件的内容。
+type cacheEntry struct {
+	URL        string      `json:"url"`
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+	Time       time.Time   `json:"time"`
+}
+
+// cachePath 返回请求对应的缓存文件，请求头不同的请求分开缓存。
+func cachePath(r Request) string {
+	headers, _ := json.Marshal(r.Headers)
+	sum := sha256.Sum256([]byte(r.URL + "\x00" + r.Profile + "\x00" + string(headers)))
+	return filepath.Join(Cfg.Fetcher.Cache.Dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// loadCache 读取有效期内的缓存。
+func loadCache(r Request) (*Response, bool) {
+	raw, err := os.ReadFile(cachePath(r))
+	if err != nil {
+		return nil, false
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(raw, &entry); err != nil || time.Since(entry.Time) > Cfg.Fetcher.Cache.TTL {
+		return nil, false
+	}
+	return &Response{StatusCode: entry.StatusCode, Header: entry.Header, Body: entry.Body}, true
+}
+
+// saveCache 写入缓存，先写临时文件再重命名，避免并发读到不完整的文件。
+func saveCache(r Request, resp *Response) error {
+	if err := os.MkdirAll(Cfg.Fetcher.Cache.Dir, 0755); err != nil {
+		return err
+	}
+	raw, err := json.Marshal(cacheEntry{
+		URL:        r.URL,
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       resp.Body,
+		Time:       time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	path := cachePath(r)
+	tmp, err := os.CreateTemp(Cfg.Fetcher.Cache.Dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,191 @@
+package fetcher
+
+import (
+	. "SecCrawler/config"
+	"SecCrawler/register"
+	"SecCrawler/store"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+)
+
+var client *http.Client
+
+// ErrNotModified 条件请求返回 304，表示上次抓取后内容没有变化。
+var ErrNotModified = fmt.Errorf("not modified: %w", register.ErrNoRecords)
+
+// Request 爬虫发起的 GET 请求。
+type Request struct {
+	URL     string
+	Profile string            // 请求头模板，默认为 browser
+	Headers map[string]string // 覆盖模板中的同名请求头
+	Crawler string            // 发起请求的爬虫，非空且开启 conditional 时按爬虫和 URL 记录校验信息并发送条件请求
+}
+
+// Response 已读取完毕的响应。
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// FetcherInit 补全请求设置的默认值，并创建共用的 HTTP 客户端。
+func FetcherInit() {
+	conf := &Cfg.Fetcher
+	if conf.Timeout <= 0 {
+		conf.Timeout = 15 * time.Second
+	}
+	if conf.Retries == 0 {
+		conf.Retries = 3
+	}
+	if conf.BackoffMin <= 0 {
+		conf.BackoffMin = time.Second
+	}
+	if conf.BackoffMax <= 0 {
+		conf.BackoffMax = 30 * time.Second
+	}
+	if conf.MaxBodySize <= 0 {
+		conf.MaxBodySize = 10 << 20
+	}
+	if conf.Concurrency <= 0 {
+		conf.Concurrency = 2
+	}
+	if conf.Cache.Dir == "" {
+		conf.Cache.Dir = "cache"
+	}
+	if conf.Cache.TTL <= 0 {
+		conf.Cache.TTL = 10 * time.Minute
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if Cfg.Proxy.CrawlerProxyEnabled {
+		proxyUrl, err := url.Parse(Cfg.Proxy.ProxyUrl)
+		if err != nil {
+			log.Fatalf("parse proxy url error: %s\n", err.Error())
+		}
+		transport.Proxy = http.ProxyURL(proxyUrl)
+	}
+	client = &http.Client{Transport: transport, Timeout: conf.Timeout}
+}
+
+// Get 发起 GET 请求并读取响应体。网络错误、429 和 5xx 会按指数退避重试，
+// 其余 4xx 直接返回错误。同一主机的请求受并发数和请求间隔限制。
+// 条件请求返回 304 时返回 ErrNotModified，收到的校验信息记录在 ctx 的 Validators 中（见 WithValidators）。
+func Get(ctx context.Context, r Request) (*Response, error) {
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		return nil, err
+	}
+	headers, ok := profile(r.Profile)
+	if !ok {
+		return nil, fmt.Errorf("unknown header profile [%s]", r.Profile)
+	}
+	if Cfg.Fetcher.Cache.Enabled {
+		if resp, ok := loadCache(r); ok {
+			return resp, nil
+		}
+	}
+	conditional := r.Crawler != "" && Cfg.Fetcher.Conditional
+	var validator store.Validator
+	if conditional {
+		validator, err = store.GetValidator(r.Crawler, r.URL)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var resp *Response
+	operation := func() error {
+		req, err := http.NewRequestWithContext(ctx, "GET", r.URL, nil)
+		if err != nil {
+			return backoff.Permanent(err)
+		}
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+		for key, value := range r.Headers {
+			req.Header.Set(key, value)
+		}
+		if validator.ETag != "" {
+			req.Header.Set("If-None-Match", validator.ETag)
+		}
+		if validator.LastModified != "" {
+			req.Header.Set("If-Modified-Since", validator.LastModified)
+		}
+
+		release, err := limiter(u.Host).acquire(ctx)
+		if err != nil {
+			return backoff.Permanent(err)
+		}
+		resp, err = do(req)
+		release()
+		return err
+	}
+
+	b := backoff.NewExponentialBackOff()
+	b.InitialInterval = Cfg.Fetcher.BackoffMin
+	b.MaxInterval = Cfg.Fetcher.BackoffMax
+	b.MaxElapsedTime = 0
+	retries := Cfg.Fetcher.Retries
+	if retries < 0 {
+		retries = 0
+	}
+	notify := func(err error, wait time.Duration) {
+		log.Printf("fetch [%s] error: %s, retry in %s\n", r.URL, err.Error(), wait.Round(time.Millisecond))
+	}
+	err = backoff.RetryNotify(operation, backoff.WithContext(backoff.WithMaxRetries(b, uint64(retries)), ctx), notify)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, ErrNotModified
+	}
+
+	// 校验信息由调用方在文章保存成功后保存
+	if v := validatorsFrom(ctx); conditional && v != nil {
+		v.add(r.Crawler, r.URL, store.Validator{
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+		})
+	}
+	if Cfg.Fetcher.Cache.Enabled && resp.StatusCode == http.StatusOK {
+		if err := saveCache(r, resp); err != nil {
+			log.Printf("save cache [%s] error: %s\n", r.URL, err.Error())
+		}
+	}
+	return resp, nil
+}
+
+// do 执行单次请求，返回的错误如果不应重试则包装为 backoff.Permanent。
+func do(req *http.Request) (*Response, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		if req.Context().Err() != nil {
+			return nil, backoff.Permanent(err)
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		return nil, fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+	if resp.StatusCode >= 400 {
+		return nil, backoff.Permanent(fmt.Errorf("unexpected status: %s", resp.Status))
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, Cfg.Fetcher.MaxBodySize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > Cfg.Fetcher.MaxBodySize {
+		return nil, backoff.Permanent(fmt.Errorf("response body exceeds %d bytes", Cfg.Fetcher.MaxBodySize))
+	}
+	return &Response{StatusCode: resp.StatusCode, Header: resp.Header, Body: body}, nil
+}
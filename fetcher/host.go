@@ -0,0 +1,76 @@
+package fetcher
+
+import (
+	. "SecCrawler/config"
+	"SecCrawler/utils"
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// hostLimiter 限制同一主机的并发请求数和请求间隔。
+type hostLimiter struct {
+	slots    chan struct{}
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time // 下一次允许发起请求的时间
+}
+
+var (
+	hostsMu sync.Mutex
+	hosts   = map[string]*hostLimiter{}
+)
+
+func limiter(host string) *hostLimiter {
+	host = strings.ToLower(host)
+	hostsMu.Lock()
+	defer hostsMu.Unlock()
+	if l, ok := hosts[host]; ok {
+		return l
+	}
+
+	concurrency, interval := Cfg.Fetcher.Concurrency, Cfg.Fetcher.Interval
+	for _, conf := range Cfg.Fetcher.Hosts {
+		if !strings.EqualFold(conf.Host, host) {
+			continue
+		}
+		if conf.Concurrency > 0 {
+			concurrency = conf.Concurrency
+		}
+		if conf.Interval > 0 {
+			interval = conf.Interval
+		}
+	}
+	l := &hostLimiter{slots: make(chan struct{}, concurrency), interval: interval}
+	hosts[host] = l
+	return l
+}
+
+// acquire 等待空闲的并发名额以及请求间隔，返回释放名额的函数。
+func (l *hostLimiter) acquire(ctx context.Context) (release func(), err error) {
+	select {
+	case l.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	release = func() { <-l.slots }
+
+	if l.interval > 0 {
+		l.mu.Lock()
+		now := time.Now()
+		wait := l.next.Sub(now)
+		if wait < 0 {
+			wait = 0
+		}
+		l.next = now.Add(wait + l.interval)
+		l.mu.Unlock()
+
+		if err := utils.Sleep(ctx, wait); err != nil {
+			release()
+			return nil, err
+		}
+	}
+	return release, nil
+}
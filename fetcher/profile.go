@@ -0,0 +1,48 @@
+package fetcher
+
+import (
+	. "SecCrawler/config"
+	"strings"
+)
+
+// 内置的请求头模板，可在 Fetcher.profiles 中覆盖。
+var builtinProfiles = map[string]map[string]string{
+	"browser": {
+		"Cache-Control":             "no-cache",
+		"Upgrade-Insecure-Requests": "1",
+		"User-Agent":                "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/96.0.4664.55 Safari/537.36",
+		"Accept":                    "text/html,application/xhtml+xml,application/xml;q=0.9,image/webp,image/apng,*/*;q=0.8,application/signed-exchange;v=b3;q=0.9",
+		"Sec-Fetch-Site":            "none",
+		"Sec-Fetch-Mode":            "navigate",
+		"Sec-Fetch-User":            "?1",
+		"Sec-Fetch-Dest":            "document",
+		"Accept-Language":           "zh-CN,zh;q=0.9",
+	},
+	"api": {
+		"Cache-Control":   "no-cache",
+		"User-Agent":      "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/119.0.0.0 Safari/537.36",
+		"Accept":          "application/json, */*",
+		"Accept-Language": "zh-CN,zh;q=0.9,en;q=0.8",
+		"Sec-Fetch-Site":  "same-origin",
+		"Sec-Fetch-Mode":  "cors",
+		"Sec-Fetch-Dest":  "empty",
+	},
+}
+
+// profile 按名称查找请求头模板（不区分大小写），自定义模板优先，名称为空时使用 browser。
+func profile(name string) (map[string]string, bool) {
+	if name == "" {
+		name = "browser"
+	}
+	for profileName, headers := range Cfg.Fetcher.Profiles {
+		if strings.EqualFold(profileName, name) {
+			return headers, true
+		}
+	}
+	for profileName, headers := range builtinProfiles {
+		if strings.EqualFold(profileName, name) {
+			return headers, true
+		}
+	}
+	return nil, false
+}
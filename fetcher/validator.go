@@ -0,0 +1,68 @@
+package fetcher
+
+import (
+	"SecCrawler/store"
+	"context"
+	"sync"
+)
+
+type validatorsKey struct{}
+
+// Validators 一次抓取中收到的缓存校验信息。校验信息只在文章和水位线保存成功后才写入存储，
+// 避免解析或保存失败时下次抓取得到 304 而永久丢失这些文章。
+type Validators struct {
+	mu    sync.Mutex
+	items []pendingValidator
+}
+
+type pendingValidator struct {
+	crawler   string
+	url       string
+	validator store.Validator
+}
+
+// WithValidators 返回记录校验信息的 ctx，Get 在该 ctx 下收到的校验信息会记录到返回的 Validators 中。
+// ctx 中没有 Validators 时不保存校验信息。
+func WithValidators(ctx context.Context) (context.Context, *Validators) {
+	v := &Validators{}
+	return context.WithValue(ctx, validatorsKey{}, v), v
+}
+
+func validatorsFrom(ctx context.Context) *Validators {
+	v, _ := ctx.Value(validatorsKey{}).(*Validators)
+	return v
+}
+
+func (v *Validators) add(crawler, url string, validator store.Validator) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.items = append(v.items, pendingValidator{crawler: crawler, url: url, validator: validator})
+}
+
+// Keep 将记录的校验信息并入 ctx 中的 Validators。由多个子订阅源组成的爬虫为每个子订阅源单独记录，
+// 只保留抓取成功的子订阅源的校验信息。
+func (v *Validators) Keep(ctx context.Context) {
+	parent := validatorsFrom(ctx)
+	if parent == nil || parent == v {
+		return
+	}
+	v.mu.Lock()
+	items := v.items
+	v.mu.Unlock()
+	for _, item := range items {
+		parent.add(item.crawler, item.url, item.validator)
+	}
+}
+
+// Save 保存记录的所有校验信息。
+func (v *Validators) Save() error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for _, item := range v.items {
+		if err := store.SaveValidator(item.crawler, item.url, item.validator); err != nil {
+			return err
+		}
+	}
+	v.items = nil
+	return nil
+}
@@ -3,26 +3,29 @@ module SecCrawler
 go 1.24.0
 
 require (
+	github.com/PuerkitoBio/goquery v1.5.1
+	github.com/cenkalti/backoff/v4 v4.1.3
 	github.com/dghubble/go-twitter v0.0.0-20221104224141-912508c3888b
+	github.com/g8rswimmer/go-twitter/v2 v2.1.5
 	github.com/gin-contrib/cors v1.3.1
 	github.com/gin-gonic/gin v1.7.7
+	github.com/gorilla/websocket v1.5.3
 	github.com/mmcdole/gofeed v1.1.3
 	github.com/n0madic/twitter-scraper v0.0.0-20231104223941-296710769dd8
 	github.com/robfig/cron v1.2.0
 	github.com/spf13/viper v1.9.0
 	github.com/tebeka/selenium v0.9.9
+	go.etcd.io/bbolt v1.3.11
 	golang.org/x/oauth2 v0.33.0
+	golang.org/x/time v0.5.0
 	gopkg.in/yaml.v2 v2.4.0
 )
 
 require (
-	github.com/PuerkitoBio/goquery v1.5.1 // indirect
 	github.com/andybalholm/cascadia v1.1.0 // indirect
 	github.com/blang/semver v3.5.1+incompatible // indirect
-	github.com/cenkalti/backoff/v4 v4.1.3 // indirect
 	github.com/dghubble/sling v1.4.0 // indirect
 	github.com/fsnotify/fsnotify v1.5.1 // indirect
-	github.com/g8rswimmer/go-twitter/v2 v2.1.5 // indirect
 	github.com/gin-contrib/sse v0.1.0 // indirect
 	github.com/go-playground/locales v0.13.0 // indirect
 	github.com/go-playground/universal-translator v0.17.0 // indirect
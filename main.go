@@ -2,17 +2,24 @@ package main
 
 import (
 	"SecCrawler/api"
+	"SecCrawler/api/auth"
 	"SecCrawler/bot"
 	"SecCrawler/config"
 	"SecCrawler/crawler"
-	"SecCrawler/register"
-	"SecCrawler/utils"
+	"SecCrawler/fetcher"
+	"SecCrawler/scheduler"
+	"SecCrawler/store"
+	"context"
 	"flag"
 	"fmt"
 	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/gin-gonic/gin"
-	"github.com/robfig/cron"
 )
 
 func init() {
@@ -22,6 +29,7 @@ func init() {
 	flag.BoolVar(&config.Help, "help", false, "print help info")
 	flag.BoolVar(&config.Generate, "init", false, "generate a config file")
 	flag.StringVar(&config.ConfigFile, "c", "config.yml", "the config `file` to be used, or generate a config file with the specified name with -init")
+	flag.StringVar(&config.HashKey, "hash", "", "print the hash of an api `key` to be used in Api.keys")
 	flag.Usage = usage
 }
 
@@ -43,25 +51,33 @@ func main() {
 		return
 	}
 
+	if config.HashKey != "" {
+		fmt.Printf("hash: %s\n", auth.Hash(config.HashKey))
+		return
+	}
+
 	config.ConfigInit()
+	fetcher.FetcherInit()
+	store.StoreInit()
+	defer store.Close()
 	bot.BotInit()
 	crawler.CrawlerInit()
 
+	// 收到退出信号时取消正在进行的抓取
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	scheduler.SchedulerInit(ctx)
+
 	if config.Test {
-		start()
+		scheduler.RunAll()
 		return
 	}
 	if config.Cfg.Cron.Enabled {
-		_cron := cron.New()
-		spec := fmt.Sprintf("0 0 %d * * ?", config.Cfg.Cron.Time)
-		err := _cron.AddFunc(spec, start)
-		// err := _cron.AddFunc("0 */1 * * * ?", start) //每分钟
+		err := scheduler.Start()
 		if err != nil {
 			log.Fatalf("add cron error: %s\n", err.Error())
 		}
-
-		_cron.Start()
-		defer _cron.Stop()
+		defer scheduler.Stop()
 	}
 
 	if config.Cfg.Api.Enabled {
@@ -69,35 +85,38 @@ func main() {
 			gin.SetMode(gin.ReleaseMode)
 		}
 
+		auth.AuthInit()
+		defer auth.StopAudit()
 		r := gin.Default()
 		api.RouterInit(r)
 		listened := fmt.Sprintf("%s:%d", config.Cfg.Api.Host, config.Cfg.Api.Port)
-		fmt.Printf("[+] API Server start at %s\n", listened)
-		err := r.Run(listened)
-		if err != nil {
+		// 请求的 ctx 派生自程序的 ctx，退出时结束实时推送等长连接，以免阻塞关闭
+		server := &http.Server{
+			Addr:        listened,
+			Handler:     r,
+			BaseContext: func(net.Listener) context.Context { return ctx },
+		}
+		go func() {
+			<-ctx.Done()
+			server.Shutdown(context.Background())
+		}()
+		var err error
+		if config.Cfg.Api.TLS.Enabled {
+			server.TLSConfig = api.TLSConfig()
+			fmt.Printf("[+] API Server start at https://%s\n", listened)
+			err = server.ListenAndServeTLS("", "")
+		} else {
+			fmt.Printf("[+] API Server start at %s\n", listened)
+			err = server.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			log.Printf("failed to start: %s", err.Error())
 		}
 	} else if config.Cfg.Cron.Enabled {
-		select {}
+		<-ctx.Done()
 	}
-
-}
-
-func start() {
-	fmt.Printf("\n[♥] crawler start at %s\n", utils.CurrentTime())
-
-	for crawlerName, crawler := range register.GetCrawlerMap() {
-		crawlerResult, err := crawler.Get()
-		if err != nil {
-			log.Printf("crawl [%s] error: %s\n\n", crawlerName, err.Error())
-			continue
-		}
-		for botName, bot := range register.GetBotMap() {
-			err := bot.Send(crawlerResult, crawler.Config().Description)
-			if err != nil {
-				log.Printf("send [%s] to [%s] error: %s\n", crawlerName, botName, err.Error())
-			}
-		}
+	if ctx.Err() != nil {
+		fmt.Println("[*] shutting down...")
 	}
 
 }
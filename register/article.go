@@ -0,0 +1,14 @@
+package register
+
+import "time"
+
+// Article 爬虫抓取到的单篇文章，在爬虫、Bot与API之间传递。
+type Article struct {
+	URL       string    `json:"url"`               // 文章链接
+	Title     string    `json:"title"`             // 文章标题
+	Published time.Time `json:"published"`         // 发布时间，站点未提供时为零值
+	Author    string    `json:"author,omitempty"`  // 作者
+	Source    string    `json:"source"`            // 来源爬虫名称
+	Summary   string    `json:"summary,omitempty"` // 摘要
+	Tags      []string  `json:"tags,omitempty"`    // 标签或分类
+}
@@ -1,21 +1,77 @@
 package register
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 type BotConfig struct {
-	Name string // Bot名称
+	Name        string // Bot名称
+	Description string // Bot描述
 }
 
 type Bot interface {
-	Config() BotConfig                                       // Bot名称
-	Send(crawlerResult [][]string, description string) error // 推送方法
+	Config() BotConfig                                 // Bot名称
+	Send(articles []Article, description string) error // 推送方法
 }
 
-var botMap = map[string]Bot{}
+// ArticleGroup 一个爬虫的待推送文章。
+type ArticleGroup struct {
+	Crawler     string    // 爬虫名称
+	Description string    // 爬虫描述
+	Articles    []Article // 待推送的文章
+}
+
+// BatchBot 可以将一次推送中所有爬虫的文章合并推送的Bot。Batch 返回 true 时，
+// 调度器在本次推送的所有爬虫抓取完成后调用一次 SendBatch，而不是为每个爬虫调用 Send。
+type BatchBot interface {
+	Bot
+	Batch() bool                           // 是否合并推送
+	SendBatch(groups []ArticleGroup) error // 合并推送方法
+}
+
+// ContextBot 推送时可能长时间等待的Bot，调度器调用 SendContext 并传入程序退出时取消的 ctx，
+// ctx 被取消时应尽快返回。
+type ContextBot interface {
+	Bot
+	SendContext(ctx context.Context, articles []Article, description string) error // 可中断的推送方法
+}
+
+// PartialError Bot只推送成功了部分文章。调度器将 Sent 中的文章标记为已推送，
+// 其余文章留在待推送队列中下次重试，避免重复推送已成功的部分。
+type PartialError struct {
+	Sent []Article // 已推送的文章
+	Err  error
+}
+
+func (e *PartialError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PartialError) Unwrap() error {
+	return e.Err
+}
+
+var (
+	botMap         = map[string]Bot{}
+	disabledBotMap = map[string]BotConfig{}
+)
 
 func RegisterBot(bot Bot) {
 	fmt.Printf("[+] register bot: [%s]\n", bot.Config().Name)
 	botMap[bot.Config().Name] = bot
+	delete(disabledBotMap, bot.Config().Name)
+}
+
+// RegisterDisabledBot 记录未启用的Bot，仅用于在API中展示。
+func RegisterDisabledBot(conf BotConfig) {
+	if _, ok := botMap[conf.Name]; !ok && conf.Name != "" {
+		disabledBotMap[conf.Name] = conf
+	}
+}
+
+func GetDisabledBotMap() map[string]BotConfig {
+	return disabledBotMap
 }
 
 func GetBotMap() map[string]Bot {
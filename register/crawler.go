@@ -1,22 +1,43 @@
 package register
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+	"time"
+)
 
 type CrawlerConfig struct {
-	Name        string // 站点名称
-	Description string // 站点描述
+	Name        string        // 站点名称
+	Description string        // 站点描述
+	Interval    time.Duration // 独立抓取间隔，为零时随推送计划抓取
+	Cron        string        // 独立抓取的cron表达式，优先于 Interval
 }
 
 type Crawler interface {
-	Config() CrawlerConfig    // 爬虫爬取的站点名称与描述
-	Get() ([][]string, error) // 爬虫爬取方法
+	Config() CrawlerConfig                                     // 爬虫爬取的站点名称与描述
+	Get(ctx context.Context, cursor Cursor) ([]Article, error) // 爬虫爬取方法，返回水位线之后的新文章，ctx 取消时应尽快返回
 }
 
-var crawlerMap = map[string]Crawler{}
+var (
+	crawlerMap         = map[string]Crawler{}
+	disabledCrawlerMap = map[string]CrawlerConfig{}
+)
 
 func RegisterCrawler(crawler Crawler) {
 	fmt.Printf("[+] register crawler: [%s]\n", crawler.Config().Name)
 	crawlerMap[crawler.Config().Name] = crawler
+	delete(disabledCrawlerMap, crawler.Config().Name)
+}
+
+// RegisterDisabledCrawler 记录未启用的爬虫，仅用于在API中展示。
+func RegisterDisabledCrawler(conf CrawlerConfig) {
+	if _, ok := crawlerMap[conf.Name]; !ok && conf.Name != "" {
+		disabledCrawlerMap[conf.Name] = conf
+	}
+}
+
+func GetDisabledCrawlerMap() map[string]CrawlerConfig {
+	return disabledCrawlerMap
 }
 
 func GetCrawlerMap() map[string]Crawler {
@@ -0,0 +1,46 @@
+package register
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrNoRecords 爬虫在水位线之后没有抓取到新文章。
+var ErrNoRecords = errors.New("no new records")
+
+// FirstRunLimit 不提供发布时间的爬虫首次运行时最多抓取的文章数。
+// 此时水位线中没有上次抓取的文章，无法判断哪些文章是新的，只取最新的几篇，避免把整个列表都当作新文章推送。
+const FirstRunLimit = 10
+
+// Cursor 爬虫增量抓取的水位线，由调度方持久化并在每次抓取时传入。
+type Cursor struct {
+	Published time.Time `json:"published"` // 已抓取文章中最新的发布时间
+	ID        string    `json:"id"`        // 已抓取的最新一篇文章的链接，用于不提供发布时间的站点
+}
+
+// IsNew 判断发布时间为t的文章是否不早于水位线，同一时刻发布的文章交由去重处理。
+func (c Cursor) IsNew(t time.Time) bool {
+	return !t.Before(c.Published)
+}
+
+// Advance 根据本次抓取结果推进水位线，articles 需按从新到旧排列。
+func (c Cursor) Advance(articles []Article) Cursor {
+	if len(articles) == 0 {
+		return c
+	}
+	next := c
+	next.ID = articles[0].URL
+	for _, article := range articles {
+		if article.Published.After(next.Published) {
+			next.Published = article.Published
+		}
+	}
+	return next
+}
+
+// SubCursorKey 返回由多个子订阅源组成的爬虫（如 Lab）中子订阅源水位线的保存键。
+// 文章的 Source 与爬虫名称不同时，调度方按该键分别保存每个子订阅源的水位线，
+// 避免某个子订阅源的新文章推进其他子订阅源的水位线。
+func SubCursorKey(crawler, source string) string {
+	return crawler + "/" + source
+}
@@ -0,0 +1,35 @@
+package scheduler
+
+import (
+	"SecCrawler/register"
+)
+
+// CrawlerSchedule 返回爬虫独立的抓取计划，随推送计划抓取时返回空字符串。
+func CrawlerSchedule(conf register.CrawlerConfig) string {
+	if conf.Cron == "" && conf.Interval <= 0 {
+		return ""
+	}
+	return describeSchedule(conf)
+}
+
+// CrawlerSchedules 返回包含该爬虫的推送计划名称。
+func CrawlerSchedules(crawlerName string) []string {
+	names := []string{}
+	for _, schedule := range schedules {
+		if contains(schedule.Crawlers, crawlerName) {
+			names = append(names, schedule.Name)
+		}
+	}
+	return names
+}
+
+// BotSchedules 返回包含该Bot的推送计划名称。
+func BotSchedules(botName string) []string {
+	names := []string{}
+	for _, schedule := range schedules {
+		if contains(schedule.Bots, botName) {
+			names = append(names, schedule.Name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,284 @@
+package scheduler
+
+import (
+	. "SecCrawler/config"
+	"SecCrawler/register"
+	"SecCrawler/store"
+	"SecCrawler/utils"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// maxJobs 保留的手动任务数量，超出时丢弃最早完成的任务。
+const maxJobs = 100
+
+// 任务以及每个爬虫、Bot的状态。
+const (
+	JobPending = "pending"
+	JobRunning = "running"
+	JobSuccess = "success"
+	JobFailed  = "failed"
+	JobSkipped = "skipped" // 只重发时不抓取爬虫
+	JobDone    = "done"
+)
+
+// Job 手动触发的抓取/推送任务。
+type Job struct {
+	ID         string                     `json:"id"`
+	Status     string                     `json:"status"` // running 或 done
+	Push       bool                       `json:"push"`
+	Resend     bool                       `json:"resend"`
+	CreatedAt  time.Time                  `json:"createdAt"`
+	FinishedAt *time.Time                 `json:"finishedAt"`
+	Crawlers   map[string]*CrawlResult    `json:"crawlers"`
+	Bots       map[string]*DeliveryResult `json:"bots"`
+}
+
+// JobOptions 手动任务的参数，Crawlers 或 Bots 为空表示全部已启用的爬虫或Bot。
+type JobOptions struct {
+	Crawlers []string
+	Bots     []string
+	Push     bool // 抓取后推送
+	Resend   bool // 推送前将今天已推送的文章重新加入待推送队列，用于重发日报，Push 为 false 时只重发不抓取
+}
+
+// CrawlResult 任务中单个爬虫的抓取结果。
+type CrawlResult struct {
+	Status string `json:"status"` // pending、running、success、failed 或 skipped
+	New    int    `json:"new"`    // 新文章数量
+	Error  string `json:"error,omitempty"`
+}
+
+// DeliveryResult 任务中单个Bot的推送结果，推送多个爬虫的文章时只要有一次失败即为 failed。
+type DeliveryResult struct {
+	Status   string   `json:"status"`             // pending、running、success 或 failed
+	Sent     int      `json:"sent"`               // 推送的文章数量
+	Requeued int      `json:"requeued,omitempty"` // 重发时重新入队的文章数量
+	Errors   []string `json:"errors,omitempty"`
+}
+
+var (
+	jobsMu sync.Mutex
+	jobs   = map[string]*Job{}
+)
+
+// Trigger 立即抓取指定的爬虫，需要推送时将待推送的文章推送给指定的Bot，返回任务快照，
+// 任务在后台执行，可通过 GetJob 查询进度。
+func Trigger(options JobOptions) (Job, error) {
+	crawlers := map[string]register.Crawler{}
+	for _, name := range options.Crawlers {
+		crawler, ok := lookupCrawler(name)
+		if !ok {
+			return Job{}, fmt.Errorf("crawler [%s] is not enabled or does not exist", name)
+		}
+		crawlers[crawler.Config().Name] = crawler
+	}
+	if len(options.Crawlers) == 0 {
+		crawlers = selectCrawlers(nil)
+	}
+	bots := map[string]register.Bot{}
+	push := options.Push || options.Resend
+	if push {
+		for _, name := range options.Bots {
+			bot, ok := lookupBot(name)
+			if !ok {
+				return Job{}, fmt.Errorf("bot [%s] is not enabled or does not exist", name)
+			}
+			bots[bot.Config().Name] = bot
+		}
+		if len(options.Bots) == 0 {
+			bots = selectBots(nil)
+		}
+	}
+	if baseCtx.Err() != nil {
+		return Job{}, baseCtx.Err()
+	}
+
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return Job{}, err
+	}
+	job := &Job{
+		ID:        hex.EncodeToString(id),
+		Status:    JobRunning,
+		Push:      push,
+		Resend:    options.Resend,
+		CreatedAt: time.Now(),
+		Crawlers:  map[string]*CrawlResult{},
+		Bots:      map[string]*DeliveryResult{},
+	}
+	for name := range crawlers {
+		job.Crawlers[name] = &CrawlResult{Status: JobPending}
+	}
+	for name := range bots {
+		job.Bots[name] = &DeliveryResult{Status: JobPending}
+	}
+
+	jobsMu.Lock()
+	jobs[job.ID] = job
+	pruneJobs()
+	snapshot := job.copy()
+	jobsMu.Unlock()
+
+	running.Add(1)
+	go runJob(job, crawlers, bots, options)
+	return snapshot, nil
+}
+
+// runJob 与推送计划相同，每个爬虫抓取后立即推送，并记录每个爬虫和Bot的结果。
+// 只重发时不抓取，重新入队后直接推送。
+func runJob(job *Job, crawlers map[string]register.Crawler, bots map[string]register.Bot, options JobOptions) {
+	defer running.Done()
+	fmt.Printf("\n[♥] [job %s] crawler start at %s\n", job.ID, utils.CurrentTime())
+
+	if options.Resend {
+		var names []string
+		for name := range crawlers {
+			names = append(names, name)
+		}
+		now := time.Now().In(Location)
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, Location)
+		for botName := range bots {
+			count, err := store.Requeue(botName, names, today)
+			updateJob(func() {
+				result := job.Bots[botName]
+				result.Requeued = count
+				if err != nil {
+					result.Errors = append(result.Errors, "requeue: "+err.Error())
+				}
+			})
+		}
+	}
+
+	var wg sync.WaitGroup
+	for crawlerName, crawler := range crawlers {
+		wg.Add(1)
+		go func(crawlerName string, crawler register.Crawler) {
+			defer wg.Done()
+			unlock := lockCrawler(crawlerName)
+			defer unlock()
+
+			crawl := options.Push || !options.Resend
+			updateJob(func() {
+				job.Crawlers[crawlerName].Status = JobRunning
+				if !crawl {
+					job.Crawlers[crawlerName].Status = JobSkipped
+				}
+				for _, result := range job.Bots {
+					if result.Status == JobPending {
+						result.Status = JobRunning
+					}
+				}
+			})
+			if crawl {
+				fresh, err := crawlAndSave(crawlerName, crawler, bots)
+				updateJob(func() {
+					result := job.Crawlers[crawlerName]
+					result.New, result.Status = fresh, JobSuccess
+					if err != nil {
+						result.Status, result.Error = JobFailed, err.Error()
+					}
+				})
+			}
+
+			recordDeliveries(job, deliver(crawlerName, crawler, bots))
+		}(crawlerName, crawler)
+	}
+	wg.Wait()
+	recordDeliveries(job, deliverBatch(crawlers, bots))
+
+	updateJob(func() {
+		for _, result := range job.Bots {
+			result.Status = JobSuccess
+			if len(result.Errors) > 0 {
+				result.Status = JobFailed
+			}
+		}
+		now := time.Now()
+		job.Status, job.FinishedAt = JobDone, &now
+	})
+	fmt.Printf("[*] [job %s] finished\n", job.ID)
+}
+
+// recordDeliveries 将推送结果累加到任务中对应的Bot。
+func recordDeliveries(job *Job, deliveries []delivery) {
+	updateJob(func() {
+		for _, d := range deliveries {
+			result := job.Bots[d.bot]
+			result.Sent += d.sent
+			if d.err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("[%s] %s", d.crawler, d.err.Error()))
+			}
+		}
+	})
+}
+
+func updateJob(update func()) {
+	jobsMu.Lock()
+	defer jobsMu.Unlock()
+	update()
+}
+
+// GetJob 返回任务的快照。
+func GetJob(id string) (Job, bool) {
+	jobsMu.Lock()
+	defer jobsMu.Unlock()
+	job, ok := jobs[id]
+	if !ok {
+		return Job{}, false
+	}
+	return job.copy(), true
+}
+
+// Jobs 返回所有保留的任务的快照，按创建时间从新到旧排列。
+func Jobs() []Job {
+	jobsMu.Lock()
+	defer jobsMu.Unlock()
+	result := make([]Job, 0, len(jobs))
+	for _, job := range jobs {
+		result = append(result, job.copy())
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.After(result[j].CreatedAt) })
+	return result
+}
+
+// pruneJobs 任务过多时丢弃最早完成的任务，需持有 jobsMu。
+func pruneJobs() {
+	for len(jobs) > maxJobs {
+		var oldest *Job
+		for _, job := range jobs {
+			if job.FinishedAt != nil && (oldest == nil || job.FinishedAt.Before(*oldest.FinishedAt)) {
+				oldest = job
+			}
+		}
+		if oldest == nil {
+			return
+		}
+		delete(jobs, oldest.ID)
+	}
+}
+
+// copy 深拷贝任务，需持有 jobsMu。
+func (job *Job) copy() Job {
+	snapshot := *job
+	if job.FinishedAt != nil {
+		finished := *job.FinishedAt
+		snapshot.FinishedAt = &finished
+	}
+	snapshot.Crawlers = map[string]*CrawlResult{}
+	for name, result := range job.Crawlers {
+		r := *result
+		snapshot.Crawlers[name] = &r
+	}
+	snapshot.Bots = map[string]*DeliveryResult{}
+	for name, result := range job.Bots {
+		r := *result
+		r.Errors = append([]string(nil), result.Errors...)
+		snapshot.Bots[name] = &r
+	}
+	return snapshot
+}
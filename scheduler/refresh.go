@@ -0,0 +1,57 @@
+package scheduler
+
+import (
+	"SecCrawler/utils"
+	"context"
+	"fmt"
+	"sync"
+)
+
+// flight 正在进行的一次手动抓取，done 关闭后 err 为抓取结果。
+type flight struct {
+	done chan struct{}
+	err  error
+}
+
+var (
+	flightsMu sync.Mutex
+	flights   = map[string]*flight{}
+)
+
+// Refresh 立即抓取爬虫并保存新文章，不推送。同一爬虫同时只会进行一次抓取，
+// 并发的调用等待并共享同一次抓取的结果。抓取在后台进行，ctx 被取消只会让调用方停止等待。
+func Refresh(ctx context.Context, crawlerName string) error {
+	crawler, ok := lookupCrawler(crawlerName)
+	if !ok {
+		return fmt.Errorf("crawler [%s] is not enabled or does not exist", crawlerName)
+	}
+	crawlerName = crawler.Config().Name
+
+	flightsMu.Lock()
+	f, ok := flights[crawlerName]
+	if !ok {
+		f = &flight{done: make(chan struct{})}
+		flights[crawlerName] = f
+		running.Add(1)
+		go func() {
+			defer running.Done()
+			unlock := lockCrawler(crawlerName)
+			fmt.Printf("\n[♥] [%s] refresh start at %s\n", crawlerName, utils.CurrentTime())
+			_, f.err = crawlAndSave(crawlerName, crawler, nil)
+			unlock()
+
+			flightsMu.Lock()
+			delete(flights, crawlerName)
+			flightsMu.Unlock()
+			close(f.done)
+		}()
+	}
+	flightsMu.Unlock()
+
+	select {
+	case <-f.done:
+		return f.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
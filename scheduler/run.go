@@ -0,0 +1,298 @@
+package scheduler
+
+import (
+	. "SecCrawler/config"
+	"SecCrawler/fetcher"
+	"SecCrawler/register"
+	"SecCrawler/store"
+	"SecCrawler/utils"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Run 执行一次推送计划：抓取计划内的爬虫，并将待推送的文章推送给计划内的Bot。
+// 配置了独立抓取计划的爬虫不在此时抓取，只推送其此前缓存的文章。
+func Run(schedule ScheduleStruct) {
+	run(schedule, false)
+}
+
+// RunAll 抓取所有爬虫并推送给所有Bot，用于 -test 模式。
+func RunAll() {
+	run(ScheduleStruct{Name: "test"}, true)
+}
+
+func run(schedule ScheduleStruct, crawlAll bool) {
+	running.Add(1)
+	defer running.Done()
+	if baseCtx.Err() != nil {
+		return
+	}
+
+	fmt.Printf("\n[♥] [%s] crawler start at %s\n", schedule.Name, utils.CurrentTime())
+
+	bots := selectBots(schedule.Bots)
+	crawlers := selectCrawlers(schedule.Crawlers)
+	var wg sync.WaitGroup
+	for crawlerName, crawler := range crawlers {
+		wg.Add(1)
+		go func(crawlerName string, crawler register.Crawler) {
+			defer wg.Done()
+			unlock := lockCrawler(crawlerName)
+			defer unlock()
+
+			if crawlAll || !hasOwnSchedule(crawler) {
+				crawlAndSave(crawlerName, crawler, bots)
+			}
+			deliver(crawlerName, crawler, bots)
+		}(crawlerName, crawler)
+	}
+	wg.Wait()
+	deliverBatch(crawlers, bots)
+}
+
+// crawlJob 返回爬虫独立抓取计划的任务，抓取结果缓存在待推送队列中，等待推送计划触发。
+func crawlJob(crawlerName string, crawler register.Crawler) func() {
+	return func() {
+		running.Add(1)
+		defer running.Done()
+		unlock := lockCrawler(crawlerName)
+		defer unlock()
+
+		fmt.Printf("\n[♥] [%s] crawler start at %s\n", crawlerName, utils.CurrentTime())
+		crawlAndSave(crawlerName, crawler, nil)
+	}
+}
+
+// crawlAndSave 抓取爬虫并保存新文章，返回新文章数量，新文章会进入订阅该爬虫的Bot以及本次推送Bot的待推送队列。
+// 文章保存成功后才推进水位线并保存条件请求的校验信息。没有新文章不视为错误。
+func crawlAndSave(crawlerName string, crawler register.Crawler, bots map[string]register.Bot) (int, error) {
+	crawlerResult, commit, err := crawl(crawlerName, crawler)
+	if errors.Is(err, register.ErrNoRecords) {
+		fmt.Printf("[*] [%s] no new records\n", crawlerName)
+		if err := commit(); err != nil {
+			log.Printf("save cursor [%s] error: %s\n", crawlerName, err.Error())
+		}
+		return 0, nil
+	}
+	if err != nil {
+		log.Printf("crawl [%s] error: %s\n\n", crawlerName, err.Error())
+		return 0, err
+	}
+
+	fresh, err := store.SaveArticles(crawlerName, crawlerResult, subscribers(crawlerName, bots))
+	if err != nil {
+		log.Printf("save [%s] error: %s\n", crawlerName, err.Error())
+		return 0, err
+	}
+	if err := commit(); err != nil {
+		log.Printf("save cursor [%s] error: %s\n", crawlerName, err.Error())
+		return 0, err
+	}
+	fmt.Printf("[*] [%s] %d new of %d articles\n", crawlerName, len(fresh), len(crawlerResult))
+	return len(fresh), nil
+}
+
+// crawl 按水位线增量抓取，首次运行时最多回溯 MaxLookback。返回的 commit 推进水位线并保存条件请求的校验信息，
+// 需在文章保存成功后调用；没有新文章时 commit 只保存校验信息。
+func crawl(crawlerName string, crawler register.Crawler) ([]register.Article, func() error, error) {
+	noop := func() error { return nil }
+	cursor, err := store.GetCursor(crawlerName)
+	if err != nil {
+		return nil, noop, err
+	}
+	if cursor.Published.IsZero() {
+		cursor.Published = time.Now().Add(-Cfg.Crawler.MaxLookback)
+	}
+
+	// 占用工作池名额，限制同时运行的爬虫数量
+	select {
+	case pool <- struct{}{}:
+		defer func() { <-pool }()
+	case <-baseCtx.Done():
+		return nil, noop, baseCtx.Err()
+	}
+	ctx, cancel := context.WithTimeout(baseCtx, Cfg.Crawler.Timeout)
+	defer cancel()
+	ctx, validators := fetcher.WithValidators(ctx)
+
+	start := time.Now()
+	articles, err := crawler.Get(ctx, cursor)
+	saveStatus(crawlerName, start, err)
+	if errors.Is(err, register.ErrNoRecords) {
+		return nil, validators.Save, err
+	}
+	if err != nil {
+		return nil, noop, err
+	}
+	commit := func() error {
+		if err := store.SaveCursor(crawlerName, cursor.Advance(articles)); err != nil {
+			return err
+		}
+		if err := saveSubCursors(crawlerName, articles); err != nil {
+			return err
+		}
+		return validators.Save()
+	}
+	return articles, commit, nil
+}
+
+// saveSubCursors 为 Source 与爬虫名称不同的文章按子订阅源分别推进水位线。
+func saveSubCursors(crawlerName string, articles []register.Article) error {
+	var sources []string
+	bySource := map[string][]register.Article{}
+	for _, article := range articles {
+		if article.Source == "" || article.Source == crawlerName {
+			continue
+		}
+		if _, ok := bySource[article.Source]; !ok {
+			sources = append(sources, article.Source)
+		}
+		bySource[article.Source] = append(bySource[article.Source], article)
+	}
+	for _, source := range sources {
+		key := register.SubCursorKey(crawlerName, source)
+		cursor, err := store.GetCursor(key)
+		if err != nil {
+			return err
+		}
+		if err := store.SaveCursor(key, cursor.Advance(bySource[source])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// saveStatus 记录本次抓取的状态，没有新文章也视为成功。
+func saveStatus(crawlerName string, start time.Time, err error) {
+	if errors.Is(err, register.ErrNoRecords) {
+		err = nil
+	}
+	if err := store.RecordCrawl(crawlerName, start, err); err != nil {
+		log.Printf("save status [%s] error: %s\n", crawlerName, err.Error())
+	}
+}
+
+// delivery 一次推送的结果，sent 为推送的文章数量。
+type delivery struct {
+	bot     string
+	crawler string
+	sent    int
+	err     error
+}
+
+// deliver 推送新文章以及此前推送失败的文章，已成功推送的Bot不会重复收到。合并推送的Bot由 deliverBatch 推送。
+func deliver(crawlerName string, crawler register.Crawler, bots map[string]register.Bot) []delivery {
+	var results []delivery
+	for botName, bot := range bots {
+		if isBatch(bot) {
+			continue
+		}
+		pending, err := store.Pending(botName, crawlerName)
+		if err != nil {
+			log.Printf("load pending [%s] for [%s] error: %s\n", crawlerName, botName, err.Error())
+			results = append(results, delivery{bot: botName, crawler: crawlerName, err: err})
+			continue
+		}
+		if len(pending) == 0 {
+			continue
+		}
+		start := time.Now()
+		err = send(bot, pending, crawler.Config().Description)
+		if err := store.RecordDelivery(botName, crawlerName, len(pending), start, err); err != nil {
+			log.Printf("save status [%s] error: %s\n", botName, err.Error())
+		}
+		if err != nil {
+			log.Printf("send [%s] to [%s] error: %s\n", crawlerName, botName, err.Error())
+			// 只推送成功了部分文章时只标记这部分，其余文章下次重试
+			sent := 0
+			var partial *register.PartialError
+			if errors.As(err, &partial) && len(partial.Sent) > 0 {
+				if err := store.MarkDelivered(botName, crawlerName, partial.Sent); err != nil {
+					log.Printf("mark [%s] delivered to [%s] error: %s\n", crawlerName, botName, err.Error())
+				}
+				sent = len(partial.Sent)
+			}
+			results = append(results, delivery{bot: botName, crawler: crawlerName, sent: sent, err: err})
+			continue
+		}
+		if err := store.MarkDelivered(botName, crawlerName, pending); err != nil {
+			log.Printf("mark [%s] delivered to [%s] error: %s\n", crawlerName, botName, err.Error())
+		}
+		results = append(results, delivery{bot: botName, crawler: crawlerName, sent: len(pending)})
+	}
+	return results
+}
+
+// send 推送文章，Bot 支持 ctx 时传入 baseCtx，程序退出时中断推送中的等待。
+func send(bot register.Bot, articles []register.Article, description string) error {
+	if bot, ok := bot.(register.ContextBot); ok {
+		return bot.SendContext(baseCtx, articles, description)
+	}
+	return bot.Send(articles, description)
+}
+
+// deliverBatch 将所有爬虫的待推送文章合并后一次推送给合并推送的Bot，每个爬虫分别记录推送结果。
+func deliverBatch(crawlers map[string]register.Crawler, bots map[string]register.Bot) []delivery {
+	var names []string
+	for name := range crawlers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var results []delivery
+	for botName, bot := range bots {
+		if !isBatch(bot) {
+			continue
+		}
+		var groups []register.ArticleGroup
+		for _, crawlerName := range names {
+			pending, err := store.Pending(botName, crawlerName)
+			if err != nil {
+				log.Printf("load pending [%s] for [%s] error: %s\n", crawlerName, botName, err.Error())
+				results = append(results, delivery{bot: botName, crawler: crawlerName, err: err})
+				continue
+			}
+			if len(pending) > 0 {
+				groups = append(groups, register.ArticleGroup{
+					Crawler:     crawlerName,
+					Description: crawlers[crawlerName].Config().Description,
+					Articles:    pending,
+				})
+			}
+		}
+		if len(groups) == 0 {
+			continue
+		}
+
+		start := time.Now()
+		err := bot.(register.BatchBot).SendBatch(groups)
+		if err != nil {
+			log.Printf("send batch to [%s] error: %s\n", botName, err.Error())
+		}
+		for _, group := range groups {
+			if err := store.RecordDelivery(botName, group.Crawler, len(group.Articles), start, err); err != nil {
+				log.Printf("save status [%s] error: %s\n", botName, err.Error())
+			}
+			if err != nil {
+				results = append(results, delivery{bot: botName, crawler: group.Crawler, err: err})
+				continue
+			}
+			if err := store.MarkDelivered(botName, group.Crawler, group.Articles); err != nil {
+				log.Printf("mark [%s] delivered to [%s] error: %s\n", group.Crawler, botName, err.Error())
+			}
+			results = append(results, delivery{bot: botName, crawler: group.Crawler, sent: len(group.Articles)})
+		}
+	}
+	return results
+}
+
+// isBatch 判断Bot是否开启了合并推送。
+func isBatch(bot register.Bot) bool {
+	batch, ok := bot.(register.BatchBot)
+	return ok && batch.Batch()
+}
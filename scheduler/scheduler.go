@@ -0,0 +1,212 @@
+package scheduler
+
+import (
+	. "SecCrawler/config"
+	"SecCrawler/register"
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron"
+)
+
+var (
+	schedules []ScheduleStruct
+	_cron     *cron.Cron
+
+	baseCtx context.Context // 进程退出时取消，用于中断正在进行的抓取
+	pool    chan struct{}   // 爬虫工作池
+	running sync.WaitGroup  // 正在执行的任务
+
+	locksMu sync.Mutex
+	locks   = map[string]*sync.Mutex{}
+)
+
+// SchedulerInit 读取推送计划并初始化工作池，未配置 schedules 时兼容旧版的每日整点推送。
+// ctx 被取消后不再启动新的抓取，正在进行的抓取会被中断。
+func SchedulerInit(ctx context.Context) {
+	baseCtx = ctx
+	workers := Cfg.Crawler.Workers
+	if workers <= 0 {
+		workers = 1
+	}
+	pool = make(chan struct{}, workers)
+	if Cfg.Crawler.Timeout <= 0 {
+		Cfg.Crawler.Timeout = 10 * time.Minute
+	}
+	// 旧版配置文件没有 MaxLookback，为 0 时首次抓取会将水位线设为当前时间而抓不到任何文章
+	if Cfg.Crawler.MaxLookback <= 0 {
+		Cfg.Crawler.MaxLookback = 24 * time.Hour
+	}
+
+	schedules = Cfg.Cron.Schedules
+	if len(schedules) == 0 {
+		schedules = []ScheduleStruct{
+			{Name: "daily", Spec: fmt.Sprintf("0 %d * * *", Cfg.Cron.Time)},
+		}
+	}
+
+	for _, schedule := range schedules {
+		for _, name := range schedule.Crawlers {
+			if _, ok := lookupCrawler(name); !ok {
+				log.Printf("schedule [%s]: crawler [%s] is not enabled or does not exist\n", schedule.Name, name)
+			}
+		}
+		for _, name := range schedule.Bots {
+			if _, ok := lookupBot(name); !ok {
+				log.Printf("schedule [%s]: bot [%s] is not enabled or does not exist\n", schedule.Name, name)
+			}
+		}
+	}
+}
+
+// Start 按配置的时区启动所有推送计划的定时任务。
+func Start() error {
+	_cron = cron.NewWithLocation(Location)
+	for _, schedule := range schedules {
+		spec, err := cron.ParseStandard(schedule.Spec)
+		if err != nil {
+			return fmt.Errorf("schedule [%s] spec [%s]: %s", schedule.Name, schedule.Spec, err.Error())
+		}
+		schedule := schedule
+		_cron.Schedule(spec, cron.FuncJob(func() { Run(schedule) }))
+		fmt.Printf("[+] register schedule: [%s] %s\n", schedule.Name, schedule.Spec)
+	}
+
+	for crawlerName, crawler := range register.GetCrawlerMap() {
+		if !hasOwnSchedule(crawler) {
+			continue
+		}
+		spec, err := crawlerSchedule(crawler.Config())
+		if err != nil {
+			return fmt.Errorf("crawler [%s]: %s", crawlerName, err.Error())
+		}
+		_cron.Schedule(spec, cron.FuncJob(crawlJob(crawlerName, crawler)))
+		fmt.Printf("[+] register crawler schedule: [%s] %s\n", crawlerName, describeSchedule(crawler.Config()))
+	}
+	_cron.Start()
+	return nil
+}
+
+// Stop 停止定时任务，并等待正在执行的任务退出。
+func Stop() {
+	if _cron != nil {
+		_cron.Stop()
+	}
+	running.Wait()
+}
+
+// hasOwnSchedule 判断爬虫是否配置了独立的抓取计划。
+func hasOwnSchedule(crawler register.Crawler) bool {
+	conf := crawler.Config()
+	return conf.Cron != "" || conf.Interval > 0
+}
+
+// crawlerSchedule 解析爬虫独立的抓取计划，cron 表达式优先于抓取间隔。
+func crawlerSchedule(conf register.CrawlerConfig) (cron.Schedule, error) {
+	if conf.Cron != "" {
+		return cron.ParseStandard(conf.Cron)
+	}
+	if conf.Interval < time.Second {
+		return nil, fmt.Errorf("interval %s is less than 1s", conf.Interval)
+	}
+	return cron.Every(conf.Interval), nil
+}
+
+func describeSchedule(conf register.CrawlerConfig) string {
+	if conf.Cron != "" {
+		return conf.Cron
+	}
+	return "@every " + conf.Interval.String()
+}
+
+// lockCrawler 锁定单个爬虫，避免推送计划与独立抓取任务并发抓取或重复推送同一爬虫。
+func lockCrawler(name string) (unlock func()) {
+	locksMu.Lock()
+	lock, ok := locks[name]
+	if !ok {
+		lock = &sync.Mutex{}
+		locks[name] = lock
+	}
+	locksMu.Unlock()
+
+	lock.Lock()
+	return lock.Unlock
+}
+
+// subscribers 返回订阅了某个爬虫的所有Bot名称（包括本次推送的Bot），新文章会加入这些Bot的待推送队列。
+func subscribers(crawlerName string, current map[string]register.Bot) []string {
+	set := map[string]bool{}
+	for botName := range current {
+		set[botName] = true
+	}
+	for _, schedule := range schedules {
+		if !contains(schedule.Crawlers, crawlerName) {
+			continue
+		}
+		for botName := range selectBots(schedule.Bots) {
+			set[botName] = true
+		}
+	}
+	var names []string
+	for name := range set {
+		names = append(names, name)
+	}
+	return names
+}
+
+// selectCrawlers 按名称筛选已注册的爬虫，names 为空时返回全部。
+func selectCrawlers(names []string) map[string]register.Crawler {
+	selected := map[string]register.Crawler{}
+	for name, crawler := range register.GetCrawlerMap() {
+		if contains(names, name) {
+			selected[name] = crawler
+		}
+	}
+	return selected
+}
+
+// selectBots 按名称筛选已注册的Bot，names 为空时返回全部。
+func selectBots(names []string) map[string]register.Bot {
+	selected := map[string]register.Bot{}
+	for name, bot := range register.GetBotMap() {
+		if contains(names, name) {
+			selected[name] = bot
+		}
+	}
+	return selected
+}
+
+func lookupCrawler(name string) (register.Crawler, bool) {
+	for crawlerName, crawler := range register.GetCrawlerMap() {
+		if strings.EqualFold(crawlerName, name) {
+			return crawler, true
+		}
+	}
+	return nil, false
+}
+
+func lookupBot(name string) (register.Bot, bool) {
+	for botName, bot := range register.GetBotMap() {
+		if strings.EqualFold(botName, name) {
+			return bot, true
+		}
+	}
+	return nil, false
+}
+
+// contains 判断 name 是否在 names 中（不区分大小写），names 为空表示全部。
+func contains(names []string, name string) bool {
+	if len(names) == 0 {
+		return true
+	}
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
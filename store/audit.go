@@ -0,0 +1,81 @@
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// auditBucket API Key 的使用记录，audit/<8字节序号> -> AuditEntry。
+var auditBucket = []byte("audit")
+
+// maxAudit 保留的使用记录数量，超出时删除最早的记录。
+const maxAudit = 10000
+
+// AuditEntry 一次 API 请求的记录，鉴权失败或权限不足时 Error 为原因。
+type AuditEntry struct {
+	Time   time.Time `json:"time"`
+	Key    string    `json:"key"` // API Key 名称，鉴权失败时为空
+	IP     string    `json:"ip"`
+	Method string    `json:"method"`
+	Path   string    `json:"path"`
+	Status int       `json:"status"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// RecordAudit 在一个事务中追加多条使用记录，并删除超出数量的最早记录。
+func RecordAudit(entries ...AuditEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	return db.Update(func(tx *bolt.Tx) error {
+		audit := tx.Bucket(auditBucket)
+		var seq uint64
+		for _, entry := range entries {
+			value, err := json.Marshal(entry)
+			if err != nil {
+				return err
+			}
+			if seq, err = audit.NextSequence(); err != nil {
+				return err
+			}
+			if err := audit.Put(eventKey(seq), value); err != nil {
+				return err
+			}
+		}
+		if seq <= maxAudit {
+			return nil
+		}
+		oldest := eventKey(seq - maxAudit + 1)
+		c := audit.Cursor()
+		for k, _ := c.First(); k != nil && bytes.Compare(k, oldest) < 0; k, _ = c.First() {
+			if err := c.Delete(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// AuditLog 返回最近的 limit 条使用记录，按时间从新到旧排列，key 不为空时只返回该 API Key 的记录。
+func AuditLog(key string, limit int) ([]AuditEntry, error) {
+	entries := []AuditEntry{}
+	err := db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(auditBucket).Cursor()
+		for k, v := c.Last(); k != nil && len(entries) < limit; k, v = c.Prev() {
+			var entry AuditEntry
+			if err := json.Unmarshal(v, &entry); err != nil {
+				return err
+			}
+			if key != "" && !strings.EqualFold(entry.Key, key) {
+				continue
+			}
+			entries = append(entries, entry)
+		}
+		return nil
+	})
+	return entries, err
+}
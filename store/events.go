@@ -0,0 +1,121 @@
+package store
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"sync"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// eventsBucket 新文章的事件日志，events/<8字节序号> -> docKey，序号单调递增，用于实时推送和断线续传。
+var eventsBucket = []byte("events")
+
+// maxEvents 保留的事件数量，超出时删除最早的事件，断线过久的客户端只能续传保留的事件。
+const maxEvents = 10000
+
+// Event 新发现文章的事件，ID 为事件序号。
+type Event struct {
+	ID uint64 `json:"id"`
+	Record
+}
+
+var subscribers = struct {
+	sync.Mutex
+	chans map[chan struct{}]bool
+}{chans: map[chan struct{}]bool{}}
+
+// Subscribe 订阅新事件的通知，有新文章保存时通道可读，之后用 Events 读取。
+// 通知会合并，处理不及时不会阻塞抓取，返回的函数用于取消订阅。
+func Subscribe() (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+	subscribers.Lock()
+	subscribers.chans[ch] = true
+	subscribers.Unlock()
+	return ch, func() {
+		subscribers.Lock()
+		delete(subscribers.chans, ch)
+		subscribers.Unlock()
+	}
+}
+
+func notify() {
+	subscribers.Lock()
+	defer subscribers.Unlock()
+	for ch := range subscribers.chans {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// appendEvent 为新保存的文章追加一条事件，并删除超出数量的最早事件。
+func appendEvent(tx *bolt.Tx, doc []byte) error {
+	events := tx.Bucket(eventsBucket)
+	seq, err := events.NextSequence()
+	if err != nil {
+		return err
+	}
+	if err := events.Put(eventKey(seq), doc); err != nil {
+		return err
+	}
+
+	// 序号连续递增，序号不大于 seq-maxEvents 的事件即为超出的事件
+	if seq <= maxEvents {
+		return nil
+	}
+	oldest := eventKey(seq - maxEvents + 1)
+	c := events.Cursor()
+	for k, _ := c.First(); k != nil && bytes.Compare(k, oldest) < 0; k, _ = c.First() {
+		if err := c.Delete(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func eventKey(id uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, id)
+	return key
+}
+
+// LastEventID 返回最新事件的序号，没有事件时为 0。
+func LastEventID() (uint64, error) {
+	var id uint64
+	err := db.View(func(tx *bolt.Tx) error {
+		if k, _ := tx.Bucket(eventsBucket).Cursor().Last(); k != nil {
+			id = binary.BigEndian.Uint64(k)
+		}
+		return nil
+	})
+	return id, err
+}
+
+// Events 按顺序返回序号大于 after 的事件，最多 limit 条。
+func Events(after uint64, limit int) ([]Event, error) {
+	var events []Event
+	err := db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(eventsBucket).Cursor()
+		for k, doc := c.Seek(eventKey(after + 1)); k != nil && len(events) < limit; k, doc = c.Next() {
+			crawler, url := splitDocKey(doc)
+			seen := lookupBucket(tx, articlesBucket, crawler)
+			if seen == nil {
+				continue
+			}
+			raw := seen.Get([]byte(url))
+			if raw == nil {
+				continue
+			}
+			event := Event{ID: binary.BigEndian.Uint64(k)}
+			if err := json.Unmarshal(raw, &event.Record); err != nil {
+				return err
+			}
+			events = append(events, event)
+		}
+		return nil
+	})
+	return events, err
+}
@@ -0,0 +1,124 @@
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// historyBucket Bot的推送历史，history/<bot>/<8字节序号> -> Delivery。
+var historyBucket = []byte("history")
+
+// maxHistory 每个Bot保留的推送历史数量，超出时删除最早的记录。
+const maxHistory = 200
+
+// Delivery 一次推送的记录。
+type Delivery struct {
+	Time     time.Time `json:"time"`
+	Crawler  string    `json:"crawler"`
+	Articles int       `json:"articles"`   // 推送的文章数量
+	Duration int64     `json:"durationMs"` // 耗时，单位为毫秒
+	Error    string    `json:"error,omitempty"`
+}
+
+// appendHistory 追加一条推送历史，并删除超出数量的最早记录。
+func appendHistory(tx *bolt.Tx, bot, crawler string, articles int, start time.Time, sendErr error) error {
+	history, err := nestedBucket(tx, historyBucket, bot)
+	if err != nil {
+		return err
+	}
+	record := Delivery{Time: start, Crawler: crawler, Articles: articles, Duration: time.Since(start).Milliseconds()}
+	if sendErr != nil {
+		record.Error = sendErr.Error()
+	}
+	value, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	seq, err := history.NextSequence()
+	if err != nil {
+		return err
+	}
+	if err := history.Put(eventKey(seq), value); err != nil {
+		return err
+	}
+
+	// 序号连续递增，序号不大于 seq-maxHistory 的记录即为超出的记录
+	if seq <= maxHistory {
+		return nil
+	}
+	oldest := eventKey(seq - maxHistory + 1)
+	c := history.Cursor()
+	for k, _ := c.First(); k != nil && bytes.Compare(k, oldest) < 0; k, _ = c.First() {
+		if err := c.Delete(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeliveryHistory 返回Bot最近的 limit 条推送记录，按时间从新到旧排列。
+// allowed 不为空时只返回其允许的爬虫的记录。
+func DeliveryHistory(bot string, limit int, allowed func(crawler string) bool) ([]Delivery, error) {
+	history := []Delivery{}
+	err := db.View(func(tx *bolt.Tx) error {
+		bucket := lookupBucket(tx, historyBucket, bot)
+		if bucket == nil {
+			return nil
+		}
+		c := bucket.Cursor()
+		for k, v := c.Last(); k != nil && len(history) < limit; k, v = c.Prev() {
+			var record Delivery
+			if err := json.Unmarshal(v, &record); err != nil {
+				return err
+			}
+			if allowed != nil && !allowed(record.Crawler) {
+				continue
+			}
+			history = append(history, record)
+		}
+		return nil
+	})
+	return history, err
+}
+
+// Requeue 将 since 之后推送给Bot的 crawlers 中爬虫的文章重新加入待推送队列，返回重新入队的文章数量。
+func Requeue(bot string, crawlers []string, since time.Time) (int, error) {
+	count := 0
+	err := db.Update(func(tx *bolt.Tx) error {
+		delivered := lookupBucket(tx, deliveredBucket, bot)
+		if delivered == nil {
+			return nil
+		}
+		for _, crawler := range crawlers {
+			bucket := delivered.Bucket([]byte(crawler))
+			if bucket == nil {
+				continue
+			}
+			var keys [][]byte
+			err := bucket.ForEach(func(key, value []byte) error {
+				t, err := time.Parse(time.RFC3339, string(value))
+				if err == nil && !t.Before(since) {
+					keys = append(keys, append([]byte(nil), key...))
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			for _, key := range keys {
+				if err := bucket.Delete(key); err != nil {
+					return err
+				}
+				if err := enqueue(tx, bot, crawler, key); err != nil {
+					return err
+				}
+				count++
+			}
+		}
+		return nil
+	})
+	return count, err
+}
@@ -0,0 +1,139 @@
+package store
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// 历史文章检索使用的索引，均为扁平的 bucket，key 以 0 字节分隔，便于前缀扫描。
+// 文章的 docKey 为 <crawler>\x00<url>。
+var (
+	indexBucket = []byte("index")
+	timeIndex   = []byte("time")  // index/time/<8字节时间><docKey>，用于按时间倒序翻页
+	termIndex   = []byte("terms") // index/terms/<term>\x00<docKey>
+	tagIndex    = []byte("tags")  // index/tags/<tag>\x00<docKey>
+	cveIndex    = []byte("cves")  // index/cves/<CVE>\x00<docKey>
+	indexMeta   = []byte("meta")  // index/meta/version
+)
+
+// indexVersion 索引格式版本，变化时启动时重建索引。
+const indexVersion = "1"
+
+func docKey(crawler, url string) []byte {
+	return []byte(crawler + "\x00" + url)
+}
+
+func splitDocKey(key []byte) (crawler, url string) {
+	parts := strings.SplitN(string(key), "\x00", 2)
+	if len(parts) != 2 {
+		return parts[0], ""
+	}
+	return parts[0], parts[1]
+}
+
+func timeKey(record Record, doc []byte) []byte {
+	key := make([]byte, 8, 8+len(doc))
+	binary.BigEndian.PutUint64(key, uint64(record.time().UnixNano()))
+	return append(key, doc...)
+}
+
+func postingKey(term string, doc []byte) []byte {
+	return append([]byte(term+"\x00"), doc...)
+}
+
+// indexRecord 将文章写入各个索引。
+func indexRecord(tx *bolt.Tx, record Record) error {
+	index := tx.Bucket(indexBucket)
+	doc := docKey(record.Crawler, record.URL)
+	if err := index.Bucket(timeIndex).Put(timeKey(record, doc), nil); err != nil {
+		return err
+	}
+
+	text := record.Title + " " + record.Summary
+	terms := index.Bucket(termIndex)
+	for _, term := range tokenize(text) {
+		if err := terms.Put(postingKey(term, doc), nil); err != nil {
+			return err
+		}
+	}
+	tags := index.Bucket(tagIndex)
+	for _, tag := range record.Tags {
+		if tag = strings.ToLower(strings.TrimSpace(tag)); tag == "" {
+			continue
+		}
+		if err := tags.Put(postingKey(tag, doc), nil); err != nil {
+			return err
+		}
+	}
+	cves := index.Bucket(cveIndex)
+	for _, cve := range extractCVEs(text + " " + strings.Join(record.Tags, " ")) {
+		if err := cves.Put(postingKey(cve, doc), nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// initIndex 创建索引，索引不存在或版本变化时用已保存的文章重建。
+func initIndex(tx *bolt.Tx) error {
+	if index := tx.Bucket(indexBucket); index != nil {
+		if meta := index.Bucket(indexMeta); meta != nil && string(meta.Get([]byte("version"))) == indexVersion {
+			return nil
+		}
+		if err := tx.DeleteBucket(indexBucket); err != nil {
+			return err
+		}
+	}
+
+	index, err := tx.CreateBucket(indexBucket)
+	if err != nil {
+		return err
+	}
+	for _, name := range [][]byte{timeIndex, termIndex, tagIndex, cveIndex, indexMeta} {
+		if _, err := index.CreateBucket(name); err != nil {
+			return err
+		}
+	}
+
+	count := 0
+	err = tx.Bucket(articlesBucket).ForEach(func(crawler, value []byte) error {
+		seen := tx.Bucket(articlesBucket).Bucket(crawler)
+		if value != nil || seen == nil {
+			return nil
+		}
+		return seen.ForEach(func(_, raw []byte) error {
+			var record Record
+			if err := json.Unmarshal(raw, &record); err != nil {
+				return err
+			}
+			count++
+			return indexRecord(tx, record)
+		})
+	})
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		fmt.Printf("[*] index %d stored articles\n", count)
+	}
+	return index.Bucket(indexMeta).Put([]byte("version"), []byte(indexVersion))
+}
+
+// postings 返回前缀为 prefix 的所有索引项对应的文章。
+func postings(bucket *bolt.Bucket, prefix string) map[string]bool {
+	docs := map[string]bool{}
+	c := bucket.Cursor()
+	p := []byte(prefix)
+	for k, _ := c.Seek(p); k != nil && strings.HasPrefix(string(k), prefix); k, _ = c.Next() {
+		i := strings.IndexByte(string(k), 0)
+		if i < 0 {
+			continue
+		}
+		docs[string(k[i+1:])] = true
+	}
+	return docs
+}
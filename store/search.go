@@ -0,0 +1,158 @@
+package store
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// SearchQuery 历史文章的检索条件，零值字段不参与过滤。
+type SearchQuery struct {
+	Text    string   // 在标题和摘要中检索，多个词之间为且的关系
+	Sources []string // 爬虫名称，不区分大小写
+	Tag     string
+	CVE     string
+	From    time.Time
+	To      time.Time
+	Cursor  string // 上一页返回的游标
+	Limit   int
+}
+
+// Search 按发布时间从新到旧检索历史文章，返回本页结果以及下一页的游标，没有下一页时游标为空。
+func Search(query SearchQuery) ([]Record, string, error) {
+	var start []byte
+	if query.Cursor != "" {
+		var err error
+		start, err = base64.RawURLEncoding.DecodeString(query.Cursor)
+		if err != nil || len(start) < 8 {
+			return nil, "", errors.New("invalid cursor")
+		}
+	} else if !query.To.IsZero() {
+		start = make([]byte, 8)
+		binary.BigEndian.PutUint64(start, uint64(query.To.UnixNano()+1))
+	}
+	words := strings.Fields(strings.ToLower(query.Text))
+
+	records := []Record{}
+	var next string
+	err := db.View(func(tx *bolt.Tx) error {
+		index := tx.Bucket(indexBucket)
+		candidates, ok := candidates(index, query)
+		if !ok {
+			return nil
+		}
+
+		var lastKey []byte
+		c := index.Bucket(timeIndex).Cursor()
+		for k := seekBefore(c, start); k != nil; k, _ = c.Prev() {
+			published := time.Unix(0, int64(binary.BigEndian.Uint64(k[:8])))
+			if !query.From.IsZero() && published.Before(query.From) {
+				break
+			}
+			doc := k[8:]
+			if candidates != nil && !candidates[string(doc)] {
+				continue
+			}
+			crawler, url := splitDocKey(doc)
+			if len(query.Sources) > 0 && !containsFold(query.Sources, crawler) {
+				continue
+			}
+			seen := lookupBucket(tx, articlesBucket, crawler)
+			if seen == nil {
+				continue
+			}
+			raw := seen.Get([]byte(url))
+			if raw == nil {
+				continue
+			}
+			var record Record
+			if err := json.Unmarshal(raw, &record); err != nil {
+				return err
+			}
+			// 索引按词匹配，这里再确认标题或摘要中包含每个检索词
+			text := strings.ToLower(record.Title + " " + record.Summary)
+			if !containsAll(text, words) {
+				continue
+			}
+
+			if len(records) == query.Limit {
+				next = base64.RawURLEncoding.EncodeToString(lastKey)
+				break
+			}
+			records = append(records, record)
+			lastKey = append(lastKey[:0], k...)
+		}
+		return nil
+	})
+	return records, next, err
+}
+
+// candidates 根据检索词、标签和 CVE 编号在索引中求交集，没有这些条件时返回 nil 表示不限制，
+// ok 为 false 表示没有符合条件的文章。
+func candidates(index *bolt.Bucket, query SearchQuery) (docs map[string]bool, ok bool) {
+	intersect := func(set map[string]bool) {
+		if docs == nil {
+			docs = set
+			return
+		}
+		for doc := range docs {
+			if !set[doc] {
+				delete(docs, doc)
+			}
+		}
+	}
+
+	for _, token := range queryTokens(query.Text) {
+		prefix := token
+		// CJK 词精确匹配，字母数字的词按前缀匹配
+		if r, _ := utf8.DecodeRuneInString(token); isCJK(r) {
+			prefix += "\x00"
+		}
+		intersect(postings(index.Bucket(termIndex), prefix))
+	}
+	if tag := strings.ToLower(strings.TrimSpace(query.Tag)); tag != "" {
+		intersect(postings(index.Bucket(tagIndex), tag+"\x00"))
+	}
+	if cve := strings.ToUpper(strings.TrimSpace(query.CVE)); cve != "" {
+		intersect(postings(index.Bucket(cveIndex), cve+"\x00"))
+	}
+	return docs, docs == nil || len(docs) > 0
+}
+
+// seekBefore 将游标移动到小于 key 的最后一项，key 为空时移动到最后一项。
+func seekBefore(c *bolt.Cursor, key []byte) []byte {
+	if key == nil {
+		k, _ := c.Last()
+		return k
+	}
+	if k, _ := c.Seek(key); k == nil {
+		k, _ = c.Last()
+		return k
+	}
+	k, _ := c.Prev()
+	return k
+}
+
+func containsAll(text string, words []string) bool {
+	for _, word := range words {
+		if !strings.Contains(text, word) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsFold(names []string, name string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+package store
+
+import (
+	. "SecCrawler/config"
+	"SecCrawler/register"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// openTestStore 在临时目录中打开数据库，测试结束时关闭。
+func openTestStore(t *testing.T) {
+	t.Helper()
+	Cfg = &Config{}
+	Cfg.Store.Path = filepath.Join(t.TempDir(), "test.db")
+	StoreInit()
+	t.Cleanup(func() {
+		Close()
+		db = nil
+	})
+}
+
+func TestSearch(t *testing.T) {
+	openTestStore(t)
+	day := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+	articles := map[string][]register.Article{
+		"Anquanke": {
+			{URL: "https://a/1", Title: "Log4j 远程代码执行漏洞分析", Summary: "CVE-2021-44228", Published: day.Add(1 * time.Hour), Tags: []string{"RCE"}},
+			{URL: "https://a/2", Title: "Spring4Shell 漏洞复现", Published: day.Add(2 * time.Hour), Tags: []string{"cve-2022-22965"}},
+			{URL: "https://a/3", Title: "内网渗透笔记", Published: day.Add(3 * time.Hour)},
+		},
+		"Xz": {
+			{URL: "https://x/1", Title: "Apache Log4j2 bypass", Published: day.Add(4 * time.Hour), Tags: []string{"rce", "java"}},
+			{URL: "https://x/2", Title: "代码审计入门", Summary: "从零开始", Published: day.Add(5 * time.Hour)},
+		},
+	}
+	for crawler, list := range articles {
+		if _, err := SaveArticles(crawler, list, nil); err != nil {
+			t.Fatalf("save articles: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		query SearchQuery
+		want  []string // 按顺序返回的文章链接
+	}{
+		{"all newest first", SearchQuery{}, []string{"https://x/2", "https://x/1", "https://a/3", "https://a/2", "https://a/1"}},
+		{"word prefix", SearchQuery{Text: "log4"}, []string{"https://x/1", "https://a/1"}},
+		{"words and", SearchQuery{Text: "log4j bypass"}, []string{"https://x/1"}},
+		{"chinese", SearchQuery{Text: "代码"}, []string{"https://x/2", "https://a/1"}},
+		{"chinese phrase", SearchQuery{Text: "代码执行"}, []string{"https://a/1"}},
+		{"chinese not adjacent", SearchQuery{Text: "代执"}, []string{}},
+		{"summary", SearchQuery{Text: "从零"}, []string{"https://x/2"}},
+		{"tag ignores case", SearchQuery{Tag: "rce"}, []string{"https://x/1", "https://a/1"}},
+		{"cve in summary", SearchQuery{CVE: "cve-2021-44228"}, []string{"https://a/1"}},
+		{"cve in tags", SearchQuery{CVE: "CVE-2022-22965"}, []string{"https://a/2"}},
+		{"sources ignore case", SearchQuery{Sources: []string{"xz"}}, []string{"https://x/2", "https://x/1"}},
+		{"source and text", SearchQuery{Sources: []string{"Anquanke"}, Text: "漏洞"}, []string{"https://a/2", "https://a/1"}},
+		{"time range", SearchQuery{From: day.Add(2 * time.Hour), To: day.Add(4 * time.Hour)}, []string{"https://x/1", "https://a/3", "https://a/2"}},
+		{"no match", SearchQuery{Text: "nothing"}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query.Limit = 10
+			records, next, err := Search(tt.query)
+			if err != nil {
+				t.Fatalf("search: %v", err)
+			}
+			if next != "" {
+				t.Errorf("next cursor = %q, want empty", next)
+			}
+			if got := recordURLs(records); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("search = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchCursor(t *testing.T) {
+	openTestStore(t)
+	start := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+	var list []register.Article
+	var want []string
+	for i := 0; i < 7; i++ {
+		url := "https://a/" + string(rune('a'+i))
+		list = append(list, register.Article{URL: url, Title: "漏洞 " + url, Published: start.Add(time.Duration(i) * time.Minute)})
+		want = append([]string{url}, want...)
+	}
+	// 两篇文章发布时间相同，翻页时也不能重复或遗漏
+	list = append(list, register.Article{URL: "https://a/same", Title: "漏洞 same", Published: start.Add(3 * time.Minute)})
+	if _, err := SaveArticles("Anquanke", list, nil); err != nil {
+		t.Fatalf("save articles: %v", err)
+	}
+
+	for _, query := range []SearchQuery{{}, {Text: "漏洞"}} {
+		var got []string
+		pages := 0
+		for cursor := ""; ; {
+			query.Cursor, query.Limit = cursor, 3
+			records, next, err := Search(query)
+			if err != nil {
+				t.Fatalf("search page %d: %v", pages, err)
+			}
+			pages++
+			got = append(got, recordURLs(records)...)
+			if next == "" {
+				break
+			}
+			if len(records) != query.Limit {
+				t.Fatalf("page %d has %d records with a next cursor", pages, len(records))
+			}
+			cursor = next
+		}
+		if pages != 3 {
+			t.Errorf("query %q: pages = %d, want 3", query.Text, pages)
+		}
+		if len(got) != len(list) || !containsInOrder(got, want) {
+			t.Errorf("query %q: paginated results = %q", query.Text, got)
+		}
+	}
+
+	if _, _, err := Search(SearchQuery{Cursor: "!!", Limit: 3}); err == nil {
+		t.Error("search with invalid cursor: want error")
+	}
+}
+
+func recordURLs(records []Record) []string {
+	urls := []string{}
+	for _, record := range records {
+		urls = append(urls, record.URL)
+	}
+	return urls
+}
+
+// containsInOrder 判断 want 中的链接在 got 中按相同顺序出现，且 got 没有重复。
+func containsInOrder(got, want []string) bool {
+	seen := map[string]bool{}
+	i := 0
+	for _, url := range got {
+		if seen[url] {
+			return false
+		}
+		seen[url] = true
+		if i < len(want) && url == want[i] {
+			i++
+		}
+	}
+	return i == len(want)
+}
@@ -0,0 +1,31 @@
+package store
+
+import (
+	"crypto/rand"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// secretsBucket 服务端生成的随机密钥，secrets/<name> -> 密钥，只保存在数据库中，不写入配置文件。
+var secretsBucket = []byte("secrets")
+
+// Secret 返回名为 name 的随机密钥，不存在时生成 size 字节的密钥并保存。
+func Secret(name string, size int) ([]byte, error) {
+	var secret []byte
+	err := db.Update(func(tx *bolt.Tx) error {
+		secrets, err := tx.CreateBucketIfNotExists(secretsBucket)
+		if err != nil {
+			return err
+		}
+		if value := secrets.Get([]byte(name)); value != nil {
+			secret = append([]byte(nil), value...)
+			return nil
+		}
+		secret = make([]byte, size)
+		if _, err := rand.Read(secret); err != nil {
+			return err
+		}
+		return secrets.Put([]byte(name), secret)
+	})
+	return secret, err
+}
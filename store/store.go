@@ -0,0 +1,449 @@
+package store
+
+import (
+	. "SecCrawler/config"
+	"SecCrawler/register"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	articlesBucket  = []byte("articles")   // articles/<crawler>/<url> -> Record
+	pendingBucket   = []byte("pending")    // pending/<bot>/<crawler>/<url> -> 入队序号
+	deliveredBucket = []byte("delivered")  // delivered/<bot>/<crawler>/<url> -> 推送时间
+	cursorsBucket   = []byte("cursors")    // cursors/<crawler> -> register.Cursor
+	validatorBucket = []byte("validators") // validators/<crawler>/<url> -> Validator
+	statusBucket    = []byte("status")     // status/<crawler> -> RunStatus
+	botStatusBucket = []byte("botStatus")  // botStatus/<bot> -> RunStatus
+)
+
+var db *bolt.DB
+
+// Record 持久化的文章记录。
+type Record struct {
+	register.Article
+	Crawler   string    `json:"crawler"`   // 抓取到该文章的爬虫
+	FirstSeen time.Time `json:"firstSeen"` // 首次抓取时间
+}
+
+// RunStatus 爬虫抓取或Bot推送的运行状态。
+type RunStatus struct {
+	LastRun       time.Time     `json:"lastRun"`             // 最近一次运行的开始时间
+	LastSuccess   time.Time     `json:"lastSuccess"`         // 最近一次成功运行的开始时间
+	LastError     string        `json:"lastError,omitempty"` // 最近一次运行的错误，成功时为空
+	LastDuration  time.Duration `json:"lastDuration"`
+	Runs          int           `json:"runs"`
+	Failures      int           `json:"failures"`
+	TotalDuration time.Duration `json:"totalDuration"`
+}
+
+// Record 记录一次从 start 开始、结果为 err 的运行。
+func (status *RunStatus) Record(start time.Time, err error) {
+	duration := time.Since(start)
+	status.LastRun = start
+	status.LastDuration = duration
+	status.Runs++
+	status.TotalDuration += duration
+	status.LastError = ""
+	if err != nil {
+		status.LastError = err.Error()
+		status.Failures++
+		return
+	}
+	status.LastSuccess = start
+}
+
+// AverageDuration 返回平均运行耗时。
+func (status RunStatus) AverageDuration() time.Duration {
+	if status.Runs == 0 {
+		return 0
+	}
+	return status.TotalDuration / time.Duration(status.Runs)
+}
+
+// Validator 上次响应的缓存校验信息，用于发送条件请求。
+type Validator struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+}
+
+// StoreInit 打开本地数据库并创建所需的bucket。
+func StoreInit() {
+	var err error
+	db, err = bolt.Open(Cfg.Store.Path, 0600, &bolt.Options{Timeout: 3 * time.Second})
+	if err != nil {
+		log.Fatalf("open store [%s] error: %s\n", Cfg.Store.Path, err.Error())
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{articlesBucket, pendingBucket, deliveredBucket, cursorsBucket, validatorBucket, statusBucket, botStatusBucket, eventsBucket, historyBucket, auditBucket, targetsBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return initIndex(tx)
+	})
+	if err != nil {
+		log.Fatalf("init store error: %s\n", err.Error())
+	}
+	fmt.Printf("[*] load store [%s] success!\n", Cfg.Store.Path)
+}
+
+// Close 关闭数据库。
+func Close() {
+	if db != nil {
+		db.Close()
+	}
+}
+
+// SaveArticles 记录爬虫抓取到的文章，返回此前从未出现过的文章，
+// 并将这些新文章加入bots中每个Bot的待推送队列。
+func SaveArticles(crawler string, articles []register.Article, bots []string) ([]register.Article, error) {
+	var fresh []register.Article
+	err := db.Update(func(tx *bolt.Tx) error {
+		seen, err := nestedBucket(tx, articlesBucket, crawler)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		for _, article := range articles {
+			key := []byte(article.URL)
+			if article.URL == "" || seen.Get(key) != nil {
+				continue
+			}
+			record := Record{Article: article, Crawler: crawler, FirstSeen: now}
+			value, err := json.Marshal(record)
+			if err != nil {
+				return err
+			}
+			if err := seen.Put(key, value); err != nil {
+				return err
+			}
+			if err := indexRecord(tx, record); err != nil {
+				return err
+			}
+			if err := appendEvent(tx, docKey(crawler, article.URL)); err != nil {
+				return err
+			}
+			for _, bot := range bots {
+				if err := enqueue(tx, bot, crawler, key); err != nil {
+					return err
+				}
+			}
+			fresh = append(fresh, article)
+		}
+		return nil
+	})
+	if err == nil && len(fresh) > 0 {
+		notify()
+	}
+	return fresh, err
+}
+
+// Pending 返回某个爬虫尚未成功推送给指定Bot的文章，按入队顺序排列。
+func Pending(bot, crawler string) ([]register.Article, error) {
+	type pendingItem struct {
+		seq     uint64
+		article register.Article
+	}
+	var items []pendingItem
+	err := db.View(func(tx *bolt.Tx) error {
+		queue := lookupBucket(tx, pendingBucket, bot, crawler)
+		seen := lookupBucket(tx, articlesBucket, crawler)
+		if queue == nil || seen == nil {
+			return nil
+		}
+		return queue.ForEach(func(k, v []byte) error {
+			raw := seen.Get(k)
+			if raw == nil {
+				return nil
+			}
+			var record Record
+			if err := json.Unmarshal(raw, &record); err != nil {
+				return err
+			}
+			items = append(items, pendingItem{seq: binary.BigEndian.Uint64(v), article: record.Article})
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(items, func(i, j int) bool { return items[i].seq < items[j].seq })
+	articles := make([]register.Article, 0, len(items))
+	for _, item := range items {
+		articles = append(articles, item.article)
+	}
+	return articles, nil
+}
+
+// MarkDelivered 将文章标记为已成功推送给指定Bot，并移出待推送队列。
+func MarkDelivered(bot, crawler string, articles []register.Article) error {
+	return db.Update(func(tx *bolt.Tx) error {
+		delivered, err := nestedBucket(tx, deliveredBucket, bot, crawler)
+		if err != nil {
+			return err
+		}
+		queue := lookupBucket(tx, pendingBucket, bot, crawler)
+		now := []byte(time.Now().Format(time.RFC3339))
+		for _, article := range articles {
+			key := []byte(article.URL)
+			if err := delivered.Put(key, now); err != nil {
+				return err
+			}
+			if queue != nil {
+				if err := queue.Delete(key); err != nil {
+					return err
+				}
+			}
+		}
+		return clearTargets(tx, bot, articles)
+	})
+}
+
+// GetCursor 读取爬虫的水位线，首次运行时返回零值。
+func GetCursor(crawler string) (register.Cursor, error) {
+	var cursor register.Cursor
+	err := db.View(func(tx *bolt.Tx) error {
+		raw := tx.Bucket(cursorsBucket).Get([]byte(crawler))
+		if raw == nil {
+			return nil
+		}
+		return json.Unmarshal(raw, &cursor)
+	})
+	return cursor, err
+}
+
+// SaveCursor 持久化爬虫的水位线。
+func SaveCursor(crawler string, cursor register.Cursor) error {
+	value, err := json.Marshal(cursor)
+	if err != nil {
+		return err
+	}
+	return db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(cursorsBucket).Put([]byte(crawler), value)
+	})
+}
+
+// RecentArticles 返回爬虫发布时间不早于 since 的文章，按发布时间从新到旧排列，
+// 没有发布时间的文章按首次抓取时间计算。
+func RecentArticles(crawler string, since time.Time) ([]register.Article, error) {
+	var records []Record
+	err := db.View(func(tx *bolt.Tx) error {
+		seen := lookupBucket(tx, articlesBucket, crawler)
+		if seen == nil {
+			return nil
+		}
+		return seen.ForEach(func(_, value []byte) error {
+			var record Record
+			if err := json.Unmarshal(value, &record); err != nil {
+				return err
+			}
+			if !record.time().Before(since) {
+				records = append(records, record)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].time().After(records[j].time())
+	})
+	articles := make([]register.Article, 0, len(records))
+	for _, record := range records {
+		articles = append(articles, record.Article)
+	}
+	return articles, nil
+}
+
+// time 返回文章的发布时间，没有发布时间时返回首次抓取时间。
+func (record Record) time() time.Time {
+	if record.Published.IsZero() {
+		return record.FirstSeen
+	}
+	return record.Published
+}
+
+// GetCrawlStatus 读取爬虫的抓取状态，从未抓取时返回零值。
+func GetCrawlStatus(crawler string) (RunStatus, error) {
+	return getStatus(statusBucket, crawler)
+}
+
+// RecordCrawl 记录爬虫一次从 start 开始、结果为 err 的抓取。
+func RecordCrawl(crawler string, start time.Time, err error) error {
+	return recordStatus(statusBucket, crawler, start, err)
+}
+
+// GetBotStatus 读取Bot的推送状态，从未推送时返回零值。
+func GetBotStatus(bot string) (RunStatus, error) {
+	return getStatus(botStatusBucket, bot)
+}
+
+// RecordDelivery 记录Bot一次从 start 开始、推送爬虫 crawler 的 articles 篇文章、结果为 err 的推送，
+// 同时写入推送历史。
+func RecordDelivery(bot, crawler string, articles int, start time.Time, err error) error {
+	return db.Update(func(tx *bolt.Tx) error {
+		if err := updateStatus(tx, botStatusBucket, bot, start, err); err != nil {
+			return err
+		}
+		return appendHistory(tx, bot, crawler, articles, start, err)
+	})
+}
+
+func getStatus(bucket []byte, name string) (RunStatus, error) {
+	var status RunStatus
+	err := db.View(func(tx *bolt.Tx) error {
+		raw := tx.Bucket(bucket).Get([]byte(name))
+		if raw == nil {
+			return nil
+		}
+		return json.Unmarshal(raw, &status)
+	})
+	return status, err
+}
+
+// recordStatus 在同一事务中读取并更新运行状态，避免并发更新丢失。
+func recordStatus(bucket []byte, name string, start time.Time, err error) error {
+	return db.Update(func(tx *bolt.Tx) error {
+		return updateStatus(tx, bucket, name, start, err)
+	})
+}
+
+func updateStatus(tx *bolt.Tx, bucket []byte, name string, start time.Time, err error) error {
+	var status RunStatus
+	if raw := tx.Bucket(bucket).Get([]byte(name)); raw != nil {
+		if err := json.Unmarshal(raw, &status); err != nil {
+			return err
+		}
+	}
+	status.Record(start, err)
+	value, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+	return tx.Bucket(bucket).Put([]byte(name), value)
+}
+
+// ArticleCount 返回爬虫已保存的文章数量。
+func ArticleCount(crawler string) (int, error) {
+	var count int
+	err := db.View(func(tx *bolt.Tx) error {
+		if seen := lookupBucket(tx, articlesBucket, crawler); seen != nil {
+			count = seen.Stats().KeyN
+		}
+		return nil
+	})
+	return count, err
+}
+
+// BotCounts 返回Bot待推送和已推送的文章数量，allowed 不为空时只统计其允许的爬虫。
+func BotCounts(bot string, allowed func(crawler string) bool) (pending, delivered int, err error) {
+	err = db.View(func(tx *bolt.Tx) error {
+		pending = countNested(lookupBucket(tx, pendingBucket, bot), allowed)
+		delivered = countNested(lookupBucket(tx, deliveredBucket, bot), allowed)
+		return nil
+	})
+	return pending, delivered, err
+}
+
+// countNested 统计 bucket 下每个子bucket的key数量之和，子bucket以爬虫名称命名。
+func countNested(bucket *bolt.Bucket, allowed func(crawler string) bool) int {
+	if bucket == nil {
+		return 0
+	}
+	count := 0
+	bucket.ForEach(func(name, value []byte) error {
+		if value == nil && (allowed == nil || allowed(string(name))) {
+			count += bucket.Bucket(name).Stats().KeyN
+		}
+		return nil
+	})
+	return count
+}
+
+// GetValidator 读取爬虫上次请求 url 时保存的校验信息，不存在时返回零值。
+func GetValidator(crawler, url string) (Validator, error) {
+	var validator Validator
+	err := db.View(func(tx *bolt.Tx) error {
+		bucket := lookupBucket(tx, validatorBucket, crawler)
+		if bucket == nil {
+			return nil
+		}
+		raw := bucket.Get([]byte(url))
+		if raw == nil {
+			return nil
+		}
+		return json.Unmarshal(raw, &validator)
+	})
+	return validator, err
+}
+
+// SaveValidator 保存爬虫请求 url 得到的校验信息，校验信息为空时删除旧记录。
+func SaveValidator(crawler, url string, validator Validator) error {
+	return db.Update(func(tx *bolt.Tx) error {
+		bucket, err := nestedBucket(tx, validatorBucket, crawler)
+		if err != nil {
+			return err
+		}
+		if validator == (Validator{}) {
+			return bucket.Delete([]byte(url))
+		}
+		value, err := json.Marshal(validator)
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(url), value)
+	})
+}
+
+// enqueue 将文章加入Bot的待推送队列，已推送过的文章不会重复入队。
+func enqueue(tx *bolt.Tx, bot, crawler string, key []byte) error {
+	if delivered := lookupBucket(tx, deliveredBucket, bot, crawler); delivered != nil && delivered.Get(key) != nil {
+		return nil
+	}
+	queue, err := nestedBucket(tx, pendingBucket, bot, crawler)
+	if err != nil {
+		return err
+	}
+	seq, err := queue.NextSequence()
+	if err != nil {
+		return err
+	}
+	value := make([]byte, 8)
+	binary.BigEndian.PutUint64(value, seq)
+	return queue.Put(key, value)
+}
+
+// nestedBucket 逐层获取或创建嵌套bucket。
+func nestedBucket(tx *bolt.Tx, root []byte, names ...string) (*bolt.Bucket, error) {
+	bucket := tx.Bucket(root)
+	for _, name := range names {
+		var err error
+		bucket, err = bucket.CreateBucketIfNotExists([]byte(name))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return bucket, nil
+}
+
+// lookupBucket 逐层查找嵌套bucket，不存在时返回nil。
+func lookupBucket(tx *bolt.Tx, root []byte, names ...string) *bolt.Bucket {
+	bucket := tx.Bucket(root)
+	for _, name := range names {
+		if bucket == nil {
+			return nil
+		}
+		bucket = bucket.Bucket([]byte(name))
+	}
+	return bucket
+}
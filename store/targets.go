@@ -0,0 +1,68 @@
+package store
+
+import (
+	"SecCrawler/register"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// targetsBucket 向多个目标推送的Bot（如 TelegramBot 的多个 chat）在每个目标上已推送的文章，
+// targets/<bot>/<target>/<url> -> 推送时间。部分目标推送失败时，重试只发给失败的目标；
+// 文章推送给所有目标后由 MarkDelivered 删除这些记录。
+var targetsBucket = []byte("targets")
+
+// TargetDelivered 返回 articles 中已推送给Bot的目标 target 的文章链接。
+func TargetDelivered(bot, target string, articles []register.Article) (map[string]bool, error) {
+	sent := map[string]bool{}
+	err := db.View(func(tx *bolt.Tx) error {
+		bucket := lookupBucket(tx, targetsBucket, bot, target)
+		if bucket == nil {
+			return nil
+		}
+		for _, article := range articles {
+			if bucket.Get([]byte(article.URL)) != nil {
+				sent[article.URL] = true
+			}
+		}
+		return nil
+	})
+	return sent, err
+}
+
+// MarkTargetDelivered 记录文章已推送给Bot的目标 target。
+func MarkTargetDelivered(bot, target string, articles []register.Article) error {
+	return db.Update(func(tx *bolt.Tx) error {
+		bucket, err := nestedBucket(tx, targetsBucket, bot, target)
+		if err != nil {
+			return err
+		}
+		now := []byte(time.Now().Format(time.RFC3339))
+		for _, article := range articles {
+			if err := bucket.Put([]byte(article.URL), now); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// clearTargets 删除文章在Bot各个目标上的推送记录。
+func clearTargets(tx *bolt.Tx, bot string, articles []register.Article) error {
+	targets := lookupBucket(tx, targetsBucket, bot)
+	if targets == nil {
+		return nil
+	}
+	return targets.ForEach(func(name, value []byte) error {
+		bucket := targets.Bucket(name)
+		if value != nil || bucket == nil {
+			return nil
+		}
+		for _, article := range articles {
+			if err := bucket.Delete([]byte(article.URL)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
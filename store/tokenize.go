@@ -0,0 +1,87 @@
+package store
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var cvePattern = regexp.MustCompile(`(?i)CVE-\d{4}-\d{4,}`)
+
+// tokenize 将文本切分为索引词：连续的字母数字按小写整词切分，
+// 汉字等 CJK 字符同时切分为单字和相邻两字，以支持不分词的中文检索。
+func tokenize(text string) []string {
+	set := map[string]bool{}
+	for _, token := range splitTokens(text, true) {
+		set[token] = true
+	}
+	tokens := make([]string, 0, len(set))
+	for token := range set {
+		tokens = append(tokens, token)
+	}
+	return tokens
+}
+
+// queryTokens 将检索词切分为索引词。CJK 字符只使用相邻两字（单字时使用单字），
+// 字母数字的词在检索时按前缀匹配。
+func queryTokens(text string) []string {
+	return splitTokens(text, false)
+}
+
+func splitTokens(text string, unigrams bool) []string {
+	var tokens []string
+	var word []rune
+	var cjk []rune
+	flushWord := func() {
+		if len(word) > 0 {
+			tokens = append(tokens, string(word))
+			word = word[:0]
+		}
+	}
+	flushCJK := func() {
+		if len(cjk) == 1 || (unigrams && len(cjk) > 0) {
+			for _, r := range cjk {
+				tokens = append(tokens, string(r))
+			}
+		}
+		for i := 0; i+1 < len(cjk); i++ {
+			tokens = append(tokens, string(cjk[i:i+2]))
+		}
+		cjk = cjk[:0]
+	}
+
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case isCJK(r):
+			flushWord()
+			cjk = append(cjk, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			flushCJK()
+			word = append(word, r)
+		default:
+			flushWord()
+			flushCJK()
+		}
+	}
+	flushWord()
+	flushCJK()
+	return tokens
+}
+
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+// extractCVEs 提取文本中出现的 CVE 编号，统一为大写。
+func extractCVEs(text string) []string {
+	set := map[string]bool{}
+	var cves []string
+	for _, match := range cvePattern.FindAllString(text, -1) {
+		cve := strings.ToUpper(match)
+		if !set[cve] {
+			set[cve] = true
+			cves = append(cves, cve)
+		}
+	}
+	return cves
+}
@@ -0,0 +1,93 @@
+package store
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"words", "Apache Log4j RCE", []string{"apache", "log4j", "rce"}},
+		{"punctuation", "CVE-2021-44228: remote-code", []string{"2021", "44228", "code", "cve", "remote"}},
+		{"duplicates", "rce RCE Rce", []string{"rce"}},
+		{"chinese", "代码执行", []string{"代", "码", "执", "行", "代码", "码执", "执行"}},
+		{"single han", "洞", []string{"洞"}},
+		{"mixed", "Log4j漏洞分析", []string{"log4j", "漏", "洞", "分", "析", "漏洞", "洞分", "分析"}},
+		{"cjk split by punctuation", "漏洞，分析", []string{"漏", "洞", "分", "析", "漏洞", "分析"}},
+		{"japanese", "カタカナ", []string{"カ", "タ", "ナ", "カタ", "タカ", "カナ"}},
+		{"hangul", "보안", []string{"보", "안", "보안"}},
+		{"empty", " \t-, ", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tokenize(tt.text)
+			want := dedupe(tt.want)
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("tokenize(%q) = %q, want %q", tt.text, got, want)
+			}
+		})
+	}
+}
+
+func TestQueryTokens(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"words", "Log4j RCE", []string{"log4j", "rce"}},
+		{"chinese bigrams only", "代码执行", []string{"代码", "码执", "执行"}},
+		{"single han", "洞", []string{"洞"}},
+		{"mixed", "log4j漏洞", []string{"log4j", "漏洞"}},
+		{"empty", "  ", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := queryTokens(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("queryTokens(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractCVEs(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"upper", "Log4Shell CVE-2021-44228", []string{"CVE-2021-44228"}},
+		{"lower", "cve-2021-44228 漏洞", []string{"CVE-2021-44228"}},
+		{"five digits", "CVE-2024-123456", []string{"CVE-2024-123456"}},
+		{"in chinese text", "关于CVE-2022-22965的分析", []string{"CVE-2022-22965"}},
+		{"duplicates keep first order", "CVE-2022-0001, cve-2021-0002 and CVE-2022-0001", []string{"CVE-2022-0001", "CVE-2021-0002"}},
+		{"too short", "CVE-2021-123 CVE-21-1234", nil},
+		{"none", "no cve here", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extractCVEs(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("extractCVEs(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func dedupe(tokens []string) []string {
+	set := map[string]bool{}
+	result := []string{}
+	for _, token := range tokens {
+		if !set[token] {
+			set[token] = true
+			result = append(result, token)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"SecCrawler/config"
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -14,11 +15,15 @@ const (
 	SITE_NOT_FOUND    = 4000
 	ARTICLE_NOT_FOUND = 4001
 	INVALID_AUTH_KEY  = 4002
+	BOT_NOT_FOUND     = 4003
+	INVALID_PARAMS    = 4004
+	JOB_NOT_FOUND     = 4005
+	PERMISSION_DENIED = 4006
+	TOO_MANY_REQUESTS = 4007
 )
 
 func CurrentTime() string {
-	time_zone := time.FixedZone("CST", 8*3600) // 8*3600 = 8h
-	n := time.Now().In(time_zone)
+	n := time.Now().In(config.Location)
 	// 获取时间，格式如2006/01/02 15:04:05
 	t := n.Format("2006/01/02 15:04:05")
 	weekMap := map[time.Weekday]string{0: "星期日", 1: "星期一", 2: "星期二", 3: "星期三", 4: "星期四", 5: "星期五", 6: "星期六"}
@@ -26,22 +31,16 @@ func CurrentTime() string {
 	return formatTime
 }
 
-func IsIn24Hours(t time.Time) bool {
-	time_zone := time.FixedZone("CST", 8*3600) // 8*3600 = 8h
-	now := time.Now().In(time_zone)
-	// 根据config生成每日整点时间
-	var hour int
-	if config.Test {
-		hour = now.Hour()
-	} else {
-		hour = int(config.Cfg.Cron.Time)
+// Sleep 等待指定时间，ctx 被取消时提前返回其错误。
+func Sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
 	}
-	cronTime := time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, time_zone)
-	subTime := cronTime.Sub(t)
-	if subTime > time.Duration(24)*time.Hour || subTime < time.Duration(0) {
-		return false
-	}
-	return true
 }
 
 func proxyClient(timeout uint8) *http.Client {
@@ -57,16 +56,6 @@ func proxyClient(timeout uint8) *http.Client {
 	return client
 }
 
-func CrawlerClient() *http.Client {
-	if config.Cfg.Proxy.CrawlerProxyEnabled {
-		return proxyClient(4)
-	}
-	client := &http.Client{
-		Timeout: time.Duration(4) * time.Second,
-	}
-	return client
-}
-
 func BotClient(timeout uint8) *http.Client {
 	if config.Cfg.Proxy.BotProxyEnabled {
 		return proxyClient(timeout)
@@ -103,6 +92,16 @@ func ErrorStrResp(c *gin.Context, code int, str string) {
 	c.Abort()
 }
 
+// ErrorStatusResp 以指定的 HTTP 状态码返回错误，用于需要客户端按状态码处理的错误，如 403、429。
+func ErrorStatusResp(c *gin.Context, status int, code int, str string) {
+	c.JSON(status, Resp{
+		Code: code,
+		Msg:  str,
+		Data: nil,
+	})
+	c.Abort()
+}
+
 func SuccessResp(c *gin.Context, data ...interface{}) {
 	if len(data) == 0 {
 		c.JSON(200, Resp{